@@ -1,16 +1,20 @@
 /*
 Copyright (c) 2016 EliteSystems. All rights reserved.
 
-eliteConfiguration lets you Load/Save Configuration from/to files with JSON content.
+eliteConfiguration lets you Load/Save Configuration from/to files with JSON (default), YAML, TOML,
+INI or Java properties content, or any content decoded/encoded by a registered Codec.
 */
 package eliteConfiguration
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"path"
 	"path/filepath"
+	"time"
 )
 
 /*
@@ -21,41 +25,82 @@ const (
 )
 
 /*
-API is a facade to the API available's methods
+API is a facade to the API available's methods. Files are decoded/encoded by the Codec registered for their extension
 */
 type API interface {
 	New(requiredName string) Configuration
 	Load(fileName string) (Configuration, error)
 	Save(configuration Configuration, fileName string) error
+	LoadDotEnv(fileName string) (Configuration, error)
+	SaveDotEnv(configuration Configuration, fileName string) error
+	LoadWith(fileName string, codec Codec) (Configuration, error)
+	SaveWith(configuration Configuration, fileName string, codec Codec) error
+	LoadFrom(reader io.Reader) (Configuration, error)
+	SaveTo(configuration Configuration, writer io.Writer) error
+	Parse(content []byte) (Configuration, error)
+	Marshal(configuration Configuration) ([]byte, error)
+	LoadFS(fsys fs.FS, name string) (Configuration, error)
+	OverlayEnvironment(configuration Configuration, options EnvironmentOptions) (Configuration, error)
 }
 
 /*
 Configuration is the main packages's interface used to manipulate configurations structs
-with a Name, a set of Values accessed by their Name and a Size
+with a Name, a set of Values accessed by their Name and a Size.
+Typed accessors (String, Int, Bool, Float, Duration, StringSlice) convert the raw Value to the requested type.
+JSON objects are stored as child Configurations, accessed with Sub or with dotted paths ("database.pool.size")
 */
 type Configuration interface {
 	Name() string
 	SetName(name string) Configuration
 	Value(name string) (interface{}, error)
 	ValueWithDefault(name string, defaultValue interface{}) interface{}
+	String(name string) string
+	StringWithDefault(name string, defaultValue string) string
+	StringValue(name string) (string, error)
+	Int(name string) int
+	IntWithDefault(name string, defaultValue int) int
+	IntValue(name string) (int, error)
+	Bool(name string) bool
+	BoolWithDefault(name string, defaultValue bool) bool
+	BoolValue(name string) (bool, error)
+	Float(name string) float64
+	FloatWithDefault(name string, defaultValue float64) float64
+	FloatValue(name string) (float64, error)
+	Duration(name string) time.Duration
+	DurationWithDefault(name string, defaultValue time.Duration) time.Duration
+	DurationValue(name string) (time.Duration, error)
+	StringSlice(name string) []string
+	StringSliceWithDefault(name string, defaultValue []string) []string
+	StringSliceValue(name string) ([]string, error)
 	Add(name string, value interface{}) Configuration
 	Remove(name string) Configuration
+	AddAll(values map[string]interface{}) Configuration
+	RemoveAll(names ...string) Configuration
+	RemovePrefix(prefix string) Configuration
+	Merge(configuration Configuration) Configuration
 	Size() int
 	Property(name string) Property
 	HasProperty(name string) bool
 	AddProperty(property Property) Configuration
+	Sub(path string) Configuration
+	Keys() []string
+	Explain(name string) []Property
 	newProperty(name string, value interface{}, orphanFlag bool) Property
+	newChild(name string) Configuration
+	ownProperty(name string) (Property, bool)
 	properties() map[string]Property
 }
 
 /*
 Property is the interface used to manipulate configurations 'properties'
-with access to their Name and Value
+with access to their Name, Value and Source
 */
 type Property interface {
 	Name() string
 	Value() interface{}
 	WithDefault(defaultValue interface{}) Property
+	Source() Source
+	WithSource(source Source) Property
 }
 
 /*
@@ -80,63 +125,102 @@ func Mutable() API {
 }
 
 /*
-newFromJSON return a new marshallableConfiguration from the jsonContent
+Concurrent return the concurrent API facade to manipulate Configurations modified in place like the mutables ones
+but safe for concurrent use
 */
-func newFromJSON(jsonContent []byte) (configuration marshallableConfiguration, messageError error) {
+func Concurrent() API {
+	return concurrentState{}
+}
+
+/*
+newFromJSON fill the configuration with the strict jsonContent. The document is a marshallableConfiguration, or a plain
+JSON object if it hasn't this shape or if plainFlag is set
+*/
+func newFromJSON(jsonContent []byte, configuration Configuration, plainFlag bool) (Configuration, error) {
 
-	// Deserialize JSON content into Configuration struct
-	if err := json.Unmarshal(jsonContent, &configuration); err != nil {
-		messageError = newError("eliteConfiguration.newFromJSON()", err)
+	document, err := decodeOrderedJSON(jsonContent)
+	if err != nil {
+		return nil, newError("eliteConfiguration.newFromJSON()", err)
+	}
+	object, isObject := document.(*orderedObject)
+	if !isObject {
+		return nil, newError("eliteConfiguration.newFromJSON()", errors.New("The JSON document must be an object"))
 	}
-	return
+	if marshallable, isMarshallable := asMarshallable(object, true); isMarshallable && !plainFlag {
+		return fromMarshallable(marshallable, configuration), nil
+	}
+	return fromPlainObject(object, configuration), nil
 }
 
 /*
-load fileName with valid JSON Content into a returned Configuration
+load fileName into a returned Configuration, decoded by the Codec registered for its extension (JSON by default)
 */
 func load(fileName string, createNew func(requiredName string) Configuration) (Configuration, error) {
 
-	jsonContent, err := readFile(fileName)
+	return loadWith(fileName, codecFor(fileName), createNew)
+}
+
+/*
+loadWith load fileName into a returned Configuration, decoded by the codec whatever its extension
+*/
+func loadWith(fileName string, codec Codec, createNew func(requiredName string) Configuration) (Configuration, error) {
+
+	content, err := readFile(fileName)
 	if err != nil {
 		return nil, err
 	}
+	return decodeFile(content, fileName, codec, createNew)
+}
 
-	// Get marshallableConfiguration from JSON
-	configuration, messageError := newFromJSON(jsonContent)
+/*
+loadFS load the file name of fsys into a returned Configuration, decoded by the Codec registered for its extension.
+The RootPath is the directory of name in fsys
+*/
+func loadFS(fsys fs.FS, name string, createNew func(requiredName string) Configuration) (Configuration, error) {
 
-	// Create new immutableConfiguration
-	if messageError == nil {
-		var returnConfiguration Configuration = createNew(configuration.NameAttr)
-		if configuration.PropertiesAttr != nil {
-			for key, value := range configuration.PropertiesAttr {
-				returnConfiguration = returnConfiguration.Add(key, value.ValueAttr)
-			}
-		}
-		// Add/Replace RootPath to configuration
-		return returnConfiguration.Add(RootPathKey, path.Dir(fileName)), nil
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, newError("fs.ReadFile("+name+")", err)
+	}
+	return decodeFile(content, name, codecFor(name), createNew)
+}
+
+/*
+decodeFile decode the content of fileName into a returned Configuration with the RootPath of fileName
+*/
+func decodeFile(content []byte, fileName string, codec Codec, createNew func(requiredName string) Configuration) (Configuration, error) {
+
+	// Decode content into a new Configuration, its properties come from fileName
+	configuration, messageError := decode(content, codec, Source{Kind: SourceFile, Name: fileName}, createNew)
+	if messageError != nil {
+		return nil, messageError
 	}
 
-	return nil, messageError
+	// Add/Replace RootPath to configuration
+	var orphanFlag = false
+	rootPath := configuration.newProperty(RootPathKey, path.Dir(fileName), orphanFlag).WithSource(Source{Kind: SourceFile, Name: fileName})
+	return configuration.AddProperty(rootPath), nil
 }
 
 /*
-save a Configuration to fileName in indented JSON format
+save a Configuration to fileName, encoded by the Codec registered for its extension (indented JSON by default)
 */
 func save(configuration Configuration, fileName string) error {
 
-	// Serialize Configuration struct to JSON
-	jsonContent, messageError := toJSON(configuration)
+	return saveWith(configuration, fileName, codecFor(fileName))
+}
 
-	if messageError == nil {
+/*
+saveWith save a Configuration to fileName, encoded by the codec whatever its extension
+*/
+func saveWith(configuration Configuration, fileName string, codec Codec) error {
 
-		// Indent JSON content for better readability
-		var jsonIndentedContent bytes.Buffer
-		if err := json.Indent(&jsonIndentedContent, jsonContent, "", "  "); err != nil {
-			messageError = newError("json.Indent()", err)
-		}
+	content, messageError := encode(codec, configuration, fileName)
+
+	if messageError == nil {
 
-		// Write JSON content to fileName
-		if err := ioutil.WriteFile(filepath.FromSlash(fileName), jsonIndentedContent.Bytes(), 0600); err != nil {
+		// Write encoded content to fileName
+		if err := ioutil.WriteFile(filepath.FromSlash(fileName), content, 0600); err != nil {
 			messageError = newError("ioutil.WriteFile("+fileName+")", err)
 		}
 	}
@@ -144,6 +228,62 @@ func save(configuration Configuration, fileName string) error {
 	return messageError
 }
 
+/*
+decode the content with the codec into a returned Configuration whose properties come from source. The files and the
+streams are all decoded by this function
+*/
+func decode(content []byte, codec Codec, source Source, createNew func(requiredName string) Configuration) (Configuration, error) {
+
+	configuration, messageError := codec.Decode(content, createNew(""))
+	if messageError != nil {
+		return nil, messageError
+	}
+	return withSources(configuration, source), nil
+}
+
+/*
+parse decode the JSON content into a returned Configuration (without RootPath)
+*/
+func parse(content []byte, createNew func(requiredName string) Configuration) (Configuration, error) {
+
+	return decode(content, JSONCodec{}, Source{Kind: SourceFile}, createNew)
+}
+
+/*
+marshal return the indented JSON content of the Configuration
+*/
+func marshal(configuration Configuration) ([]byte, error) {
+
+	return JSONCodec{}.Encode(configuration)
+}
+
+/*
+loadFrom read the JSON content of reader into a returned Configuration (without RootPath)
+*/
+func loadFrom(reader io.Reader, createNew func(requiredName string) Configuration) (Configuration, error) {
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, newError("ioutil.ReadAll()", err)
+	}
+	return parse(content, createNew)
+}
+
+/*
+saveTo write the indented JSON content of the Configuration to writer
+*/
+func saveTo(configuration Configuration, writer io.Writer) error {
+
+	content, messageError := marshal(configuration)
+	if messageError != nil {
+		return messageError
+	}
+	if _, err := writer.Write(content); err != nil {
+		return newError("io.Writer.Write()", err)
+	}
+	return nil
+}
+
 /*
 toJSON return JSON's content from the Configuration
 */
@@ -158,20 +298,104 @@ func toJSON(configuration Configuration) ([]byte, error) {
 }
 
 /*
-toMarshallable convert a Configuration to a marshallableConfiguration
+toMarshallable convert a Configuration to a marshallableConfiguration. Child Configurations are converted too
 */
 func toMarshallable(configuration Configuration) marshallableConfiguration {
 
 	returnConfiguration := marshallableConfiguration{NameAttr: configuration.Name(), PropertiesAttr: make(map[string]marshallableProperty)}
-	if configuration.properties() != nil {
-		for key, value := range configuration.properties() {
-			returnConfiguration.PropertiesAttr[key] = marshallableProperty{NameAttr: value.Name(), ValueAttr: value.Value()}
-		}
+	for key, value := range configuration.properties() {
+		returnConfiguration.PropertiesAttr[key] = toMarshallableProperty(value)
 	}
 
 	return returnConfiguration
 }
 
+/*
+toMarshallableProperty convert a Property to a marshallableProperty. Child Configurations are converted too
+*/
+func toMarshallableProperty(property Property) marshallableProperty {
+
+	if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+		return marshallableProperty{NameAttr: property.Name(), ValueAttr: toMarshallable(child)}
+	}
+	return marshallableProperty{NameAttr: property.Name(), ValueAttr: property.Value()}
+}
+
+/*
+fromMarshallable fill the configuration with an object of the marshallableConfiguration shape, in the file's order
+and with the positions of the properties' keys. Nested marshallableConfigurations and JSON objects become child
+Configurations
+*/
+func fromMarshallable(marshallable *orderedObject, configuration Configuration) Configuration {
+
+	var returnConfiguration = configuration
+	if name, isString := marshallable.values[NameKey].(string); isString {
+		returnConfiguration = returnConfiguration.SetName(name)
+	}
+	properties, isObject := marshallable.values["properties"].(*orderedObject)
+	if !isObject {
+		return returnConfiguration
+	}
+	for _, key := range properties.keys {
+		value := properties.values[key].(*orderedObject).values["value"]
+		if child, isMarshallable := asMarshallable(value, false); isMarshallable {
+			value = fromMarshallable(child, returnConfiguration.newChild(key))
+		} else if object, isObject := value.(*orderedObject); isObject {
+			value = fromObject(object, returnConfiguration.newChild(key))
+		} else {
+			value = plainValue(value)
+		}
+		returnConfiguration = addLiteral(returnConfiguration, key, value)
+		if position, located := properties.positions[key]; located {
+			property, _ := returnConfiguration.ownProperty(key)
+			returnConfiguration = returnConfiguration.AddProperty(property.WithSource(position))
+		}
+	}
+	return returnConfiguration
+}
+
+/*
+asMarshallable return the decoded JSON value as an object if it has the marshallableConfiguration format
+({"name": ..., "properties": {"key": {"name": ..., "value": ...}}}). The "properties" of a root document can be null
+or missing, like in an empty marshallableConfiguration
+*/
+func asMarshallable(value interface{}, rootFlag bool) (*orderedObject, bool) {
+
+	object, isObject := value.(*orderedObject)
+	if !isObject || (object.values["properties"] == nil && !rootFlag) {
+		return nil, false
+	}
+
+	for _, key := range object.keys {
+		if key != NameKey && key != "properties" {
+			return nil, false
+		}
+	}
+	if _, isString := object.values[NameKey].(string); !isString && object.values[NameKey] != nil {
+		return nil, false
+	}
+	if object.values["properties"] == nil {
+		return object, true
+	}
+
+	properties, isObject := object.values["properties"].(*orderedObject)
+	if !isObject {
+		return nil, false
+	}
+	for _, key := range properties.keys {
+		property, isObject := properties.values[key].(*orderedObject)
+		if !isObject {
+			return nil, false
+		}
+		_, hasValue := property.values["value"]
+		_, hasName := property.values[NameKey]
+		if !hasValue || len(property.keys) > 2 || (len(property.keys) == 2 && !hasName) {
+			return nil, false
+		}
+	}
+	return object, true
+}
+
 /*
 newError return a new configurationError with required message and optional cause
 */
@@ -2,7 +2,7 @@ package eliteConfiguration
 
 const (
 	version = "0"
-	release = "3"
+	release = "4"
 	hotfix  = "0"
 	feature = "0"
 )
@@ -0,0 +1,223 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/*
+Codec is the interface used to decode/encode a file content from/to a Configuration.
+Decode fill the empty configuration given (created by the API facade) and return it.
+The numbers keep the type of their format: JSON decodes them as float64, YAML and TOML decode the integers as int and
+the other numbers as float64. The typed accessors (Int, Float, ...) convert both, so they return the same values
+whatever the Codec
+*/
+type Codec interface {
+	Decode(content []byte, configuration Configuration) (Configuration, error)
+	Encode(configuration Configuration) ([]byte, error)
+}
+
+/*
+DocumentCodec is a Codec able to Save a Configuration by patching the content of the existing file
+(to keep its comments and layout). Patch is used instead of Encode when the file exists
+*/
+type DocumentCodec interface {
+	Codec
+	Patch(document []byte, configuration Configuration) ([]byte, error)
+}
+
+/*
+JSONCodec is the default Codec, reading and writing the indented JSON format of marshallableConfiguration.
+Unless Strict is set, the files can have line (//) and block comments, trailing commas, unquoted keys and single
+quoted strings (JSONC/JSON5 like). Files are always saved in strict JSON.
+Files can also be plain JSON objects ({"Key1": "Value1"}), the shape is detected when loading unless Plain is set and
+Plain selects it when saving. The optional string "name" member of a plain object is the Configuration's Name, and
+the Name of a Configuration without "name" property is saved as "name" (and so loaded back as its Name).
+When Preserve is set, saving an existing file only changes the text of the modified properties, in the file's shape
+*/
+type JSONCodec struct {
+	Strict   bool
+	Plain    bool
+	Preserve bool
+}
+
+var (
+	codecsMutex  sync.RWMutex
+	defaultCodec Codec = JSONCodec{}
+	codecs             = map[string]Codec{
+		".json":       JSONCodec{},
+		".jsonc":      JSONCodec{},
+		".json5":      JSONCodec{},
+		".yaml":       YAMLCodec{},
+		".yml":        YAMLCodec{},
+		".toml":       TOMLCodec{},
+		".ini":        INICodec{},
+		".properties": PropertiesCodec{},
+		".env":        DotEnvCodec{},
+	}
+)
+
+/*
+RegisterCodec register (or replace) the Codec used to Load/Save the files with the extension (".json", ".yaml", ...)
+*/
+func RegisterCodec(extension string, codec Codec) {
+
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+	codecs[normalizeExtension(extension)] = codec
+}
+
+/*
+codecFor return the Codec registered for the fileName's extension, or the JSON Codec if there's none
+*/
+func codecFor(fileName string) Codec {
+
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	if codec, exist := codecs[normalizeExtension(filepath.Ext(fileName))]; exist {
+		return codec
+	}
+	return defaultCodec
+}
+
+/*
+normalizeExtension return the lower case extension with its leading dot
+*/
+func normalizeExtension(extension string) string {
+
+	extension = strings.ToLower(extension)
+	if extension != "" && !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	return extension
+}
+
+/*
+Decode the JSON content into the configuration
+*/
+func (codec JSONCodec) Decode(content []byte, configuration Configuration) (Configuration, error) {
+
+	if !codec.Strict {
+		standardContent, messageError := standardJSON(content)
+		if messageError != nil {
+			return nil, messageError
+		}
+		content = standardContent
+	}
+
+	return newFromJSON(content, configuration, codec.Plain)
+}
+
+/*
+Encode the configuration in indented JSON, in the plain shape if Plain is set
+*/
+func (codec JSONCodec) Encode(configuration Configuration) ([]byte, error) {
+
+	var jsonContent []byte
+	var messageError error
+	if codec.Plain {
+		if jsonContent, messageError = encodePlainJSON(configuration); messageError != nil {
+			messageError = newError("eliteConfiguration.JSONCodec.Encode()", messageError)
+		}
+	} else {
+		jsonContent, messageError = toJSON(configuration)
+	}
+	if messageError != nil {
+		return nil, messageError
+	}
+
+	// Indent JSON content for better readability
+	var jsonIndentedContent bytes.Buffer
+	if err := json.Indent(&jsonIndentedContent, jsonContent, "", "  "); err != nil {
+		return nil, newError("json.Indent()", err)
+	}
+	return jsonIndentedContent.Bytes(), nil
+}
+
+/*
+orderedObject is an object decoded by a Codec which keeps the order of its keys
+*/
+type orderedObject struct {
+	keys      []string
+	values    map[string]interface{}
+	positions map[string]Source
+}
+
+/*
+newOrderedObject return a new empty orderedObject
+*/
+func newOrderedObject() *orderedObject {
+	return &orderedObject{values: make(map[string]interface{})}
+}
+
+/*
+set add or replace the value of key, a new key is added at the end
+*/
+func (object *orderedObject) set(key string, value interface{}) {
+
+	if _, exist := object.values[key]; !exist {
+		object.keys = append(object.keys, key)
+	}
+	object.values[key] = value
+}
+
+/*
+locate keep the line and the column (starting at 1) of the key in the decoded content
+*/
+func (object *orderedObject) locate(key string, line int, column int) {
+
+	if object.positions == nil {
+		object.positions = make(map[string]Source)
+	}
+	object.positions[key] = Source{Line: line, Column: column}
+}
+
+/*
+fromObject fill the configuration with the object's values, in the object's order, with their known positions.
+Nested objects become child Configurations, objects inside arrays become raw JSON objects
+*/
+func fromObject(object *orderedObject, configuration Configuration) Configuration {
+
+	for _, key := range object.keys {
+		value := object.values[key]
+		if child, isObject := value.(*orderedObject); isObject {
+			value = fromObject(child, configuration.newChild(key))
+		} else {
+			value = plainValue(value)
+		}
+		configuration = addLiteral(configuration, key, value)
+		if position, located := object.positions[key]; located {
+			property, _ := configuration.ownProperty(key)
+			configuration = configuration.AddProperty(property.WithSource(position))
+		}
+	}
+	return configuration
+}
+
+/*
+plainValue return the value where all the orderedObjects are converted to raw JSON objects
+*/
+func plainValue(value interface{}) interface{} {
+
+	switch typedValue := value.(type) {
+	case *orderedObject:
+		values := make(map[string]interface{}, len(typedValue.keys))
+		for _, key := range typedValue.keys {
+			values[key] = plainValue(typedValue.values[key])
+		}
+		return values
+	case []interface{}:
+		items := make([]interface{}, len(typedValue))
+		for index, item := range typedValue {
+			items[index] = plainValue(item)
+		}
+		return items
+	}
+	return value
+}
@@ -0,0 +1,540 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+/*
+concurrentConfiguration is an internal Configuration struct safe for concurrent use, modified in place like the
+mutable one. A RWMutex guards the properties of the whole hierarchy: the child Configurations share the mutex of
+their root, a child of another concurrentConfiguration is copied when added and the returned Properties are immutable
+snapshots whose slices and maps are copies
+*/
+type concurrentConfiguration struct {
+	iMutex         *sync.RWMutex
+	iConfiguration *mutableConfiguration
+}
+
+/*
+newConcurrentConfiguration return a new empty concurrentConfiguration with the requiredName
+*/
+func newConcurrentConfiguration(requiredName string) *concurrentConfiguration {
+	return &concurrentConfiguration{iMutex: &sync.RWMutex{}, iConfiguration: &mutableConfiguration{iName: requiredName}}
+}
+
+/*
+Name get the configuration's Name
+*/
+func (configuration *concurrentConfiguration) Name() string {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Name()
+}
+
+/*
+SetName set a new name to the configuration returned
+*/
+func (configuration *concurrentConfiguration) SetName(requiredName string) Configuration {
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	configuration.iConfiguration.SetName(requiredName)
+	return configuration
+}
+
+/*
+Value return the raw(untyped) Value of a specified named Property. If Property doesn't exist an error is returned.
+*/
+func (configuration *concurrentConfiguration) Value(requiredName string) (interface{}, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	value, err := configuration.iConfiguration.Value(requiredName)
+	return configuration.shared(value), err
+}
+
+/*
+ValueWithDefault return the raw(untyped) Value of a specified named Property or the specified defaultValue if Property doesn't exist
+*/
+func (configuration *concurrentConfiguration) ValueWithDefault(requiredName string, requiredDefaultValue interface{}) interface{} {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.shared(configuration.iConfiguration.ValueWithDefault(requiredName, requiredDefaultValue))
+}
+
+/*
+String return the Value of a specified named Property converted to string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) String(requiredName string) string {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.String(requiredName)
+}
+
+/*
+StringWithDefault return the Value of a specified named Property converted to string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) StringWithDefault(requiredName string, requiredDefaultValue string) string {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.StringWithDefault(requiredName, requiredDefaultValue)
+}
+
+/*
+StringValue return the Value of a specified named Property converted to string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *concurrentConfiguration) StringValue(requiredName string) (string, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.StringValue(requiredName)
+}
+
+/*
+Int return the Value of a specified named Property converted to int, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) Int(requiredName string) int {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Int(requiredName)
+}
+
+/*
+IntWithDefault return the Value of a specified named Property converted to int, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) IntWithDefault(requiredName string, requiredDefaultValue int) int {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.IntWithDefault(requiredName, requiredDefaultValue)
+}
+
+/*
+IntValue return the Value of a specified named Property converted to int. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *concurrentConfiguration) IntValue(requiredName string) (int, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.IntValue(requiredName)
+}
+
+/*
+Bool return the Value of a specified named Property converted to bool, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) Bool(requiredName string) bool {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Bool(requiredName)
+}
+
+/*
+BoolWithDefault return the Value of a specified named Property converted to bool, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) BoolWithDefault(requiredName string, requiredDefaultValue bool) bool {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.BoolWithDefault(requiredName, requiredDefaultValue)
+}
+
+/*
+BoolValue return the Value of a specified named Property converted to bool. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *concurrentConfiguration) BoolValue(requiredName string) (bool, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.BoolValue(requiredName)
+}
+
+/*
+Float return the Value of a specified named Property converted to float64, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) Float(requiredName string) float64 {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Float(requiredName)
+}
+
+/*
+FloatWithDefault return the Value of a specified named Property converted to float64, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) FloatWithDefault(requiredName string, requiredDefaultValue float64) float64 {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.FloatWithDefault(requiredName, requiredDefaultValue)
+}
+
+/*
+FloatValue return the Value of a specified named Property converted to float64. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *concurrentConfiguration) FloatValue(requiredName string) (float64, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.FloatValue(requiredName)
+}
+
+/*
+Duration return the Value of a specified named Property converted to time.Duration, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) Duration(requiredName string) time.Duration {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Duration(requiredName)
+}
+
+/*
+DurationWithDefault return the Value of a specified named Property converted to time.Duration, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) DurationWithDefault(requiredName string, requiredDefaultValue time.Duration) time.Duration {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.DurationWithDefault(requiredName, requiredDefaultValue)
+}
+
+/*
+DurationValue return the Value of a specified named Property converted to time.Duration. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *concurrentConfiguration) DurationValue(requiredName string) (time.Duration, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.DurationValue(requiredName)
+}
+
+/*
+StringSlice return the Value of a specified named Property converted to []string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) StringSlice(requiredName string) []string {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.StringSlice(requiredName)
+}
+
+/*
+StringSliceWithDefault return the Value of a specified named Property converted to []string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *concurrentConfiguration) StringSliceWithDefault(requiredName string, requiredDefaultValue []string) []string {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.StringSliceWithDefault(requiredName, requiredDefaultValue)
+}
+
+/*
+StringSliceValue return the Value of a specified named Property converted to []string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *concurrentConfiguration) StringSliceValue(requiredName string) ([]string, error) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.StringSliceValue(requiredName)
+}
+
+/*
+Add a Property to the Configuration returned
+*/
+func (configuration *concurrentConfiguration) Add(requiredName string, optionalValue interface{}) Configuration {
+
+	ownedValue := configuration.owned(optionalValue)
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	configuration.iConfiguration.Add(requiredName, ownedValue)
+	return configuration
+}
+
+/*
+Remove a property to the Configuration returned
+*/
+func (configuration *concurrentConfiguration) Remove(requiredName string) Configuration {
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	configuration.iConfiguration.Remove(requiredName)
+	return configuration
+}
+
+/*
+AddAll add the values (by name or dotted path) to the Configuration returned, in the order of their sorted names
+*/
+func (configuration *concurrentConfiguration) AddAll(values map[string]interface{}) Configuration {
+
+	ownedValues := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		ownedValues[key] = configuration.owned(value)
+	}
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	configuration.iConfiguration.AddAll(ownedValues)
+	return configuration
+}
+
+/*
+RemoveAll remove the properties with the names (or dotted paths) from the Configuration returned
+*/
+func (configuration *concurrentConfiguration) RemoveAll(requiredNames ...string) Configuration {
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	configuration.iConfiguration.RemoveAll(requiredNames...)
+	return configuration
+}
+
+/*
+RemovePrefix remove the properties whose dotted path starts with the prefix ("database." or "cache_") from the
+Configuration returned
+*/
+func (configuration *concurrentConfiguration) RemovePrefix(requiredPrefix string) Configuration {
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	configuration.iConfiguration.RemovePrefix(requiredPrefix)
+	return configuration
+}
+
+/*
+Merge add copies of all the properties of the configuration (keeping their Source) to the Configuration returned, a
+property replacing the whole existing one. The properties are read before the lock so a Configuration can merge its
+children
+*/
+func (configuration *concurrentConfiguration) Merge(requiredConfiguration Configuration) Configuration {
+
+	properties := mergedProperties(requiredConfiguration, configuration)
+	ownedValues := make([]interface{}, len(properties))
+	for index, property := range properties {
+		ownedValues[index] = configuration.owned(property.Value())
+	}
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	for index, property := range properties {
+		var orphanFlag = false
+		configuration.iConfiguration.AddProperty(immutableProperty{iName: property.Name(), iValue: ownedValues[index], iOrphan: orphanFlag, iSource: property.Source()})
+	}
+	return configuration
+}
+
+/*
+Size return the size of the configuration (Number of properties)
+*/
+func (configuration *concurrentConfiguration) Size() int {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Size()
+}
+
+/*
+Property always return a Property with the requiredName. The Configuration one if exists, a new one else
+*/
+func (configuration *concurrentConfiguration) Property(requiredName string) Property {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.snapshot(configuration.iConfiguration.Property(requiredName))
+}
+
+/*
+HasProperty check if the named Property exist or not in the Configuration
+*/
+func (configuration *concurrentConfiguration) HasProperty(requiredName string) bool {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.HasProperty(requiredName)
+}
+
+/*
+AddProperty add a Property to the Configuration returned
+*/
+func (configuration *concurrentConfiguration) AddProperty(property Property) Configuration {
+
+	ownedValue := configuration.owned(property.Value())
+
+	configuration.iMutex.Lock()
+	defer configuration.iMutex.Unlock()
+	var orphanFlag = false
+	configuration.iConfiguration.AddProperty(immutableProperty{iName: property.Name(), iValue: ownedValue, iOrphan: orphanFlag, iSource: property.Source()})
+	return configuration
+}
+
+/*
+Keys return the names of all the properties of the configuration in their insertion order
+*/
+func (configuration *concurrentConfiguration) Keys() []string {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.iConfiguration.Keys()
+}
+
+/*
+Explain return the Property with the requiredName (in a slice), or an empty slice if it doesn't exist
+*/
+func (configuration *concurrentConfiguration) Explain(requiredName string) []Property {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	properties := configuration.iConfiguration.Explain(requiredName)
+	for index, property := range properties {
+		properties[index] = configuration.snapshot(property)
+	}
+	return properties
+}
+
+/*
+Sub always return the child Configuration with the requiredPath. The Configuration one if exists, a new empty one else
+*/
+func (configuration *concurrentConfiguration) Sub(requiredPath string) Configuration {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	return configuration.shared(configuration.iConfiguration.Sub(requiredPath)).(Configuration)
+}
+
+/*
+newChild instantiate and return an appropriate child Configuration, sharing the mutex so it's stored as is
+*/
+func (configuration *concurrentConfiguration) newChild(requiredName string) Configuration {
+	return &concurrentConfiguration{iMutex: configuration.iMutex, iConfiguration: &mutableConfiguration{iName: requiredName}}
+}
+
+/*
+newProperty instantiate and return an appropriate Configuration's Property
+*/
+func (configuration *concurrentConfiguration) newProperty(requiredName string, optionalValue interface{}, orphanFlag bool) Property {
+	return immutableProperty{iName: requiredName, iValue: optionalValue, iOrphan: orphanFlag}
+}
+
+/*
+ownProperty return a snapshot of the Property named exactly requiredName
+*/
+func (configuration *concurrentConfiguration) ownProperty(requiredName string) (Property, bool) {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	if property, exist := configuration.iConfiguration.ownProperty(requiredName); exist {
+		return configuration.snapshot(property), true
+	}
+	return nil, false
+}
+
+/*
+properties return a snapshot of all the properties of the configuration
+*/
+func (configuration *concurrentConfiguration) properties() map[string]Property {
+
+	configuration.iMutex.RLock()
+	defer configuration.iMutex.RUnlock()
+	properties := make(map[string]Property, len(configuration.iConfiguration.iProperties))
+	for key, property := range configuration.iConfiguration.iProperties {
+		properties[key] = configuration.snapshot(property)
+	}
+	return properties
+}
+
+/*
+shared return the value, a child Configuration being returned as a concurrentConfiguration sharing the mutex and
+the slices and maps as copies which can be modified without the lock
+*/
+func (configuration *concurrentConfiguration) shared(value interface{}) interface{} {
+
+	if child, isMutable := value.(*mutableConfiguration); isMutable {
+		return &concurrentConfiguration{iMutex: configuration.iMutex, iConfiguration: child}
+	}
+	return copiedValue(value)
+}
+
+/*
+owned return the value to store, a concurrentConfiguration sharing the mutex being stored as its mutable content.
+A concurrentConfiguration guarded by another mutex is copied, so no operation ever holds both mutexes. It must be
+called before locking the configuration
+*/
+func (configuration *concurrentConfiguration) owned(value interface{}) interface{} {
+
+	child, isConcurrent := value.(*concurrentConfiguration)
+	if !isConcurrent {
+		return value
+	}
+	if child.iMutex != configuration.iMutex {
+		copied, _ := copiedConfiguration(configuration.newChild(child.Name()), child, "", MergeOptions{})
+		child = copied.(*concurrentConfiguration)
+	}
+	return child.iConfiguration
+}
+
+/*
+copiedValue return a deep copy of the slices and maps of the value, the other values being returned as is
+*/
+func copiedValue(value interface{}) interface{} {
+
+	switch typedValue := value.(type) {
+	case []interface{}:
+		if typedValue == nil {
+			return value
+		}
+		items := make([]interface{}, len(typedValue))
+		for index, item := range typedValue {
+			items[index] = copiedValue(item)
+		}
+		return items
+	case map[string]interface{}:
+		if typedValue == nil {
+			return value
+		}
+		values := make(map[string]interface{}, len(typedValue))
+		for key, item := range typedValue {
+			values[key] = copiedValue(item)
+		}
+		return values
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch {
+	case reflected.Kind() == reflect.Slice && !reflected.IsNil():
+		copied := reflect.MakeSlice(reflected.Type(), reflected.Len(), reflected.Len())
+		reflect.Copy(copied, reflected)
+		return copied.Interface()
+	case reflected.Kind() == reflect.Map && !reflected.IsNil():
+		copied := reflect.MakeMapWithSize(reflected.Type(), reflected.Len())
+		for _, key := range reflected.MapKeys() {
+			copied.SetMapIndex(key, reflected.MapIndex(key))
+		}
+		return copied.Interface()
+	}
+	return value
+}
+
+/*
+snapshot return an immutable copy of the property which can be used without the lock
+*/
+func (configuration *concurrentConfiguration) snapshot(property Property) Property {
+
+	var orphanFlag = false
+	if mutable, isMutable := property.(*mutableProperty); isMutable {
+		orphanFlag = mutable.iOrphan
+	} else if immutable, isImmutable := property.(immutableProperty); isImmutable {
+		orphanFlag = immutable.iOrphan
+	}
+	return immutableProperty{iName: property.Name(), iValue: configuration.shared(property.Value()), iOrphan: orphanFlag, iSource: property.Source()}
+}
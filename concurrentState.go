@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"io"
+	"io/fs"
+)
+
+/*
+concurrentState is the stateless API facade struct used to manipulate concurrent Configurations
+*/
+type concurrentState struct {
+}
+
+/*
+New return a new Configuration with the required Name
+*/
+func (state concurrentState) New(requiredName string) Configuration {
+
+	return newConcurrentConfiguration(requiredName)
+}
+
+/*
+Load fileName into a returned Configuration, decoded by the Codec registered for its extension (JSON by default)
+*/
+func (state concurrentState) Load(fileName string) (Configuration, error) {
+
+	return load(fileName, state.New)
+}
+
+/*
+Save a Configuration to fileName, encoded by the Codec registered for its extension (indented JSON by default)
+*/
+func (state concurrentState) Save(configuration Configuration, fileName string) error {
+
+	return save(configuration, fileName)
+}
+
+/*
+LoadDotEnv fileName with KEY=VALUE lines (dotenv format, whatever its extension) into a returned Configuration
+*/
+func (state concurrentState) LoadDotEnv(fileName string) (Configuration, error) {
+
+	return loadWith(fileName, DotEnvCodec{}, state.New)
+}
+
+/*
+SaveDotEnv a Configuration to fileName with KEY=VALUE lines (dotenv format, whatever its extension)
+*/
+func (state concurrentState) SaveDotEnv(configuration Configuration, fileName string) error {
+
+	return saveWith(configuration, fileName, DotEnvCodec{})
+}
+
+/*
+LoadWith fileName into a returned Configuration, decoded by the codec whatever its extension (JSONCodec{Plain: true}
+to read a JSON file as a plain object even if it looks like the marshallableConfiguration format)
+*/
+func (state concurrentState) LoadWith(fileName string, codec Codec) (Configuration, error) {
+
+	return loadWith(fileName, codec, state.New)
+}
+
+/*
+SaveWith a Configuration to fileName, encoded by the codec whatever its extension (JSONCodec{Preserve: true} to keep
+the comments and the layout of an existing file)
+*/
+func (state concurrentState) SaveWith(configuration Configuration, fileName string, codec Codec) error {
+
+	return saveWith(configuration, fileName, codec)
+}
+
+/*
+LoadFrom reader with JSON Content (decoded by JSONCodec, whatever the registered Codecs) into a returned Configuration
+*/
+func (state concurrentState) LoadFrom(reader io.Reader) (Configuration, error) {
+
+	return loadFrom(reader, state.New)
+}
+
+/*
+SaveTo a Configuration to writer in indented JSON format (encoded by JSONCodec, whatever the registered Codecs)
+*/
+func (state concurrentState) SaveTo(configuration Configuration, writer io.Writer) error {
+
+	return saveTo(configuration, writer)
+}
+
+/*
+Parse JSON Content (decoded by JSONCodec, whatever the registered Codecs) into a returned Configuration
+*/
+func (state concurrentState) Parse(content []byte) (Configuration, error) {
+
+	return parse(content, state.New)
+}
+
+/*
+Marshal a Configuration in indented JSON format (encoded by JSONCodec, whatever the registered Codecs)
+*/
+func (state concurrentState) Marshal(configuration Configuration) ([]byte, error) {
+
+	return marshal(configuration)
+}
+
+/*
+LoadFS the file name of fsys (embed.FS, os.DirFS, ...) into a returned Configuration, with a RootPath relative to fsys
+*/
+func (state concurrentState) LoadFS(fsys fs.FS, name string) (Configuration, error) {
+
+	return loadFS(fsys, name, state.New)
+}
+
+/*
+OverlayEnvironment return a Layered Configuration with the environment variables selected by options over the
+configuration, converted to the type of the values they replace
+*/
+func (state concurrentState) OverlayEnvironment(configuration Configuration, options EnvironmentOptions) (Configuration, error) {
+
+	return overlayEnvironment(configuration, options, state.New)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"strings"
+)
+
+/*
+addValues add the values to the configuration in the order of their sorted names. The properties are added in one
+batch with addProperties, the dotted paths designating a child being grouped to add them into the child at once.
+A dotted path whose child is also one of the names is added after the batch, as Add() would do
+*/
+func addValues(configuration Configuration, values map[string]interface{}, addProperties func(configuration Configuration, properties []Property) Configuration) Configuration {
+
+	var pending []Property
+	var deferred []string
+	children := make(map[string]map[string]interface{})
+	childIndexes := make(map[string]int)
+	for _, key := range sortedKeys(values) {
+
+		if prefix, rest, isChild := valueChild(configuration, key); isChild {
+			if _, isName := values[prefix]; isName {
+				deferred = append(deferred, key)
+				continue
+			}
+			if _, exist := children[prefix]; !exist {
+				children[prefix] = make(map[string]interface{})
+				childIndexes[prefix] = len(pending)
+				pending = append(pending, nil)
+			}
+			children[prefix][rest] = values[key]
+			continue
+		}
+
+		var orphanFlag = false
+		pending = append(pending, configuration.newProperty(key, childValue(configuration, key, values[key]), orphanFlag))
+	}
+
+	// Each child gets all its values at once, in place of its first name
+	for prefix, childValues := range children {
+		child, source := childOf(configuration, prefix)
+		var orphanFlag = false
+		pending[childIndexes[prefix]] = configuration.newProperty(prefix, child.AddAll(childValues), orphanFlag).WithSource(source)
+	}
+	configuration = addProperties(configuration, pending)
+
+	for _, key := range deferred {
+		configuration = configuration.Add(key, values[key])
+	}
+	return configuration
+}
+
+/*
+mergedProperties return the properties of source in its order, as properties of target keeping their Source.
+The child Configurations are copied, so target shares nothing with source
+*/
+func mergedProperties(source Configuration, target Configuration) []Property {
+
+	keys := source.Keys()
+	properties := make([]Property, 0, len(keys))
+	for _, key := range keys {
+		if property, exist := source.ownProperty(key); exist {
+			value := property.Value()
+			if child, isConfiguration := value.(Configuration); isConfiguration {
+
+				// Copying into an empty Configuration can't fail
+				value, _ = copiedConfiguration(target.newChild(key), child, key, MergeOptions{})
+			}
+			var orphanFlag = false
+			properties = append(properties, target.newProperty(key, value, orphanFlag).WithSource(property.Source()))
+		}
+	}
+	return properties
+}
+
+/*
+removeNames remove the properties with the names from the configuration. The names are removed by batches with
+removeProperties, the dotted paths designating a child being removed from it
+*/
+func removeNames(configuration Configuration, names []string, removeProperties func(configuration Configuration, names []string) Configuration) Configuration {
+
+	var pending []string
+	for _, name := range names {
+		if strings.Contains(name, PathSeparator) {
+			configuration, pending = removeProperties(configuration, pending), nil
+			if returnConfiguration, removed := removeFromChild(configuration, name); removed {
+				configuration = returnConfiguration
+				continue
+			}
+		}
+		pending = append(pending, name)
+	}
+	return removeProperties(configuration, pending)
+}
+
+/*
+removePrefix remove the properties whose dotted path starts with prefix, in the child Configurations too (the
+children left empty are removed). The names of the configuration are removed in one batch with removeProperties
+*/
+func removePrefix(configuration Configuration, prefix string, removeProperties func(configuration Configuration, names []string) Configuration) Configuration {
+
+	var names []string
+	for _, key := range configuration.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			names = append(names, key)
+			continue
+		}
+		if !strings.HasPrefix(prefix, key+PathSeparator) {
+			continue
+		}
+		if property, exist := configuration.ownProperty(key); exist {
+			if child, isConfiguration := property.Value().(Configuration); isConfiguration && child.Size() > 0 {
+
+				// A child left empty is removed with the prefixed names
+				child = child.RemovePrefix(prefix[len(key)+len(PathSeparator):])
+				if child.Size() == 0 {
+					names = append(names, key)
+					continue
+				}
+				var orphanFlag = false
+				configuration = configuration.AddProperty(configuration.newProperty(key, child, orphanFlag).WithSource(property.Source()))
+			}
+		}
+	}
+	return removeProperties(configuration, names)
+}
+
+/*
+addEachProperty add the properties one by one
+*/
+func addEachProperty(configuration Configuration, properties []Property) Configuration {
+
+	for _, property := range properties {
+		configuration = configuration.AddProperty(property)
+	}
+	return configuration
+}
+
+/*
+removeEachName remove the properties with the names one by one
+*/
+func removeEachName(configuration Configuration, names []string) Configuration {
+
+	for _, name := range names {
+		configuration = configuration.Remove(name)
+	}
+	return configuration
+}
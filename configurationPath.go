@@ -0,0 +1,189 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+PathSeparator separates the names of nested Configurations in a dotted path ("database.pool.size")
+*/
+const (
+	PathSeparator = "."
+)
+
+/*
+lookupProperty return the Property with the requiredPath. An existing Property named with the whole path
+always wins over the nested ones
+*/
+func lookupProperty(configuration Configuration, requiredPath string) (Property, bool) {
+
+	if property, exist := configuration.ownProperty(requiredPath); exist {
+		return property, true
+	}
+	if child, _, rest, found := childPath(configuration, requiredPath); found {
+		return lookupProperty(child, rest)
+	}
+	return nil, false
+}
+
+/*
+childPath split requiredPath on the first child Configuration found in its prefixes. The child is not searched
+if a Property is named with the whole path
+*/
+func childPath(configuration Configuration, requiredPath string) (child Configuration, prefix string, rest string, found bool) {
+
+	if _, exist := configuration.ownProperty(requiredPath); exist {
+		return nil, "", "", false
+	}
+
+	for index := strings.Index(requiredPath, PathSeparator); index >= 0; {
+
+		prefix = requiredPath[:index]
+		if property, exist := configuration.ownProperty(prefix); exist {
+			if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+				return child, prefix, requiredPath[index+len(PathSeparator):], true
+			}
+		}
+
+		next := strings.Index(requiredPath[index+len(PathSeparator):], PathSeparator)
+		if next < 0 {
+			break
+		}
+		index += len(PathSeparator) + next
+	}
+	return nil, "", "", false
+}
+
+/*
+valueChild split the dotted path on the child Configuration it designates, an existing one or a new one named with
+its first name if neither the whole path nor the first name are already used
+*/
+func valueChild(configuration Configuration, requiredPath string) (prefix string, rest string, isChild bool) {
+
+	if _, prefix, rest, found := childPath(configuration, requiredPath); found {
+		return prefix, rest, true
+	}
+	index := strings.Index(requiredPath, PathSeparator)
+	if index <= 0 {
+		return "", "", false
+	}
+	if _, exist := configuration.ownProperty(requiredPath); exist {
+		return "", "", false
+	}
+	if _, exist := configuration.ownProperty(requiredPath[:index]); exist {
+		return "", "", false
+	}
+	return requiredPath[:index], requiredPath[index+len(PathSeparator):], true
+}
+
+/*
+childOf return the child Configuration named prefix with the Source of its Property, or a new one if there's none
+*/
+func childOf(configuration Configuration, prefix string) (Configuration, Source) {
+
+	if property, exist := configuration.ownProperty(prefix); exist {
+		if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+			return child, property.Source()
+		}
+	}
+	return configuration.newChild(prefix), Source{}
+}
+
+/*
+addToChild add the value into the child Configuration designated by requiredPath (created if needed)
+and return the Configuration with the modified child. Return false if requiredPath doesn't designate a child
+*/
+func addToChild(configuration Configuration, requiredPath string, optionalValue interface{}) (Configuration, bool) {
+
+	prefix, rest, isChild := valueChild(configuration, requiredPath)
+	if !isChild {
+		return configuration, false
+	}
+
+	child, source := childOf(configuration, prefix)
+	var orphanFlag = false
+	return configuration.AddProperty(configuration.newProperty(prefix, child.Add(rest, optionalValue), orphanFlag).WithSource(source)), true
+}
+
+/*
+removeFromChild remove the Property designated by requiredPath from its child Configuration and return
+the Configuration with the modified child. Return false if requiredPath doesn't designate a child
+*/
+func removeFromChild(configuration Configuration, requiredPath string) (Configuration, bool) {
+
+	if child, prefix, rest, found := childPath(configuration, requiredPath); found {
+		var orphanFlag = false
+		return configuration.AddProperty(configuration.newProperty(prefix, child.Remove(rest), orphanFlag)), true
+	}
+	return configuration, false
+}
+
+/*
+subConfiguration always return a Configuration for requiredPath. The child one if exists, a new empty one else
+*/
+func subConfiguration(configuration Configuration, requiredPath string) Configuration {
+
+	if property, found := lookupProperty(configuration, requiredPath); found {
+		if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+			return child
+		}
+	}
+	return configuration.newChild(requiredPath[strings.LastIndex(requiredPath, PathSeparator)+1:])
+}
+
+/*
+childValue return the value to store in the configuration: raw JSON objects become child Configurations
+*/
+func childValue(configuration Configuration, requiredName string, optionalValue interface{}) interface{} {
+
+	values, isObject := optionalValue.(map[string]interface{})
+	if !isObject {
+		return optionalValue
+	}
+
+	child := configuration.newChild(requiredName)
+	for _, key := range sortedKeys(values) {
+		child = addLiteral(child, key, values[key])
+	}
+	return child
+}
+
+/*
+addLiteral add a Property named exactly requiredName (a dotted name doesn't create children)
+*/
+func addLiteral(configuration Configuration, requiredName string, optionalValue interface{}) Configuration {
+
+	var orphanFlag = false
+	return configuration.AddProperty(configuration.newProperty(requiredName, childValue(configuration, requiredName, optionalValue), orphanFlag))
+}
+
+/*
+sortedKeys return the keys of a raw JSON object in a stable order
+*/
+func sortedKeys(values map[string]interface{}) []string {
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/*
+removedName return a copy of names without the name
+*/
+func removedName(names []string, name string) []string {
+
+	namesCopy := make([]string, 0, len(names))
+	for _, existingName := range names {
+		if existingName != name {
+			namesCopy = append(namesCopy, existingName)
+		}
+	}
+	return namesCopy
+}
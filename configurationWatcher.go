@@ -0,0 +1,382 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+/*
+Kinds of Change
+*/
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+/*
+Change of a Property between two Configurations: its Kind (ChangeAdded, ChangeRemoved or ChangeModified) and its
+Old and New Property (nil when it didn't exist)
+*/
+type Change struct {
+	Kind string
+	Old  Property
+	New  Property
+}
+
+/*
+ChangeSet is the set of the Changes between two Configurations, by dotted path ("database.pool.size")
+*/
+type ChangeSet map[string]Change
+
+/*
+ChangeCallback is called by a Watcher with the reloaded Configuration and its Changes
+*/
+type ChangeCallback func(configuration Configuration, changes ChangeSet)
+
+/*
+WatchOptions configure a Watcher: the Debounce delay waited after the last write before reloading (100ms by
+default), and the PollInterval (1s by default) of the polling used if Poll is set or if the system can't notify the
+writes (inotify on Linux).
+The reloaded Configurations are only applied if all the Validators accept them. With LastKnownGood, each valid file
+is copied next to it (LastKnownGoodName) and the Watcher starts on this copy if the file is invalid
+*/
+type WatchOptions struct {
+	Debounce      time.Duration
+	PollInterval  time.Duration
+	Poll          bool
+	Validators    []Validator
+	LastKnownGood bool
+}
+
+/*
+Watcher reload a file with the immutable API when it's written, and call its callbacks with the new Configuration.
+The Watcher is safe for concurrent use
+*/
+type Watcher struct {
+	iFileName       string
+	iOptions        WatchOptions
+	iNotifier       fileNotifier
+	iReload         sync.Mutex
+	iMutex          sync.Mutex
+	iLive           *Live
+	iStartError     error
+	iCallbacks      []ChangeCallback
+	iErrorCallbacks []func(err error)
+	iDone           chan struct{}
+	iClose          sync.Once
+}
+
+/*
+fileNotifier send an event when the watched file may have been written
+*/
+type fileNotifier interface {
+	Events() <-chan struct{}
+	Close() error
+}
+
+/*
+Watch Load fileName with the immutable API and return a Watcher reloading it each time it's written
+*/
+func Watch(fileName string, options WatchOptions) (*Watcher, error) {
+
+	if options.Debounce <= 0 {
+		options.Debounce = 100 * time.Millisecond
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+
+	// An invalid file is replaced by its last known good copy, its error being kept as StartError
+	configuration, content, startError := loadValidated(fileName, codecFor(fileName), options.Validators)
+	if startError == nil && options.LastKnownGood {
+		startError = saveLastKnownGood(fileName, content)
+	} else if startError != nil {
+		if !options.LastKnownGood {
+			return nil, startError
+		}
+		lastKnownGood, err := LoadLastKnownGood(fileName, options.Validators...)
+		if err != nil {
+			return nil, startError
+		}
+		configuration = lastKnownGood
+	}
+
+	// The file is polled if the system can't notify its writes
+	var notifier fileNotifier
+	if !options.Poll {
+		notifier, _ = newSystemNotifier(fileName)
+	}
+	if notifier == nil {
+		notifier = newPollingNotifier(fileName, options.PollInterval)
+	}
+
+	watcher := &Watcher{iFileName: fileName, iOptions: options, iNotifier: notifier, iLive: NewLive(configuration), iStartError: startError, iDone: make(chan struct{})}
+	go watcher.run()
+	return watcher, nil
+}
+
+/*
+FileName return the name of the watched file
+*/
+func (watcher *Watcher) FileName() string {
+	return watcher.iFileName
+}
+
+/*
+Configuration return the last Configuration loaded
+*/
+func (watcher *Watcher) Configuration() Configuration {
+	return watcher.iLive.Get()
+}
+
+/*
+Live return the Live holding the last Configuration loaded, to be shared with the readers
+*/
+func (watcher *Watcher) Live() *Live {
+	return watcher.iLive
+}
+
+/*
+StartError return the error which made the Watcher start on the last known good copy of the file (or the error
+writing this copy), nil if the file was valid
+*/
+func (watcher *Watcher) StartError() error {
+	return watcher.iStartError
+}
+
+/*
+OnChange register a callback called after each reload changing the Configuration
+*/
+func (watcher *Watcher) OnChange(callback ChangeCallback) {
+
+	watcher.iMutex.Lock()
+	defer watcher.iMutex.Unlock()
+	watcher.iCallbacks = append(watcher.iCallbacks, callback)
+}
+
+/*
+OnError register a callback called when a reload fails (invalid file or Configuration refused by a Validator), the
+last Configuration being kept. A last known good copy which can't be written is also reported
+*/
+func (watcher *Watcher) OnError(callback func(err error)) {
+
+	watcher.iMutex.Lock()
+	defer watcher.iMutex.Unlock()
+	watcher.iErrorCallbacks = append(watcher.iErrorCallbacks, callback)
+}
+
+/*
+Reload the file now, validate it and call the callbacks, return the error of the reload. The reloads are serialized
+*/
+func (watcher *Watcher) Reload() error {
+
+	watcher.iReload.Lock()
+	defer watcher.iReload.Unlock()
+
+	configuration, content, err := loadValidated(watcher.iFileName, codecFor(watcher.iFileName), watcher.iOptions.Validators)
+	if err == nil && watcher.iOptions.LastKnownGood {
+
+		// The valid Configuration is applied even if its copy can't be written
+		if copyError := saveLastKnownGood(watcher.iFileName, content); copyError != nil {
+			defer watcher.reportError(copyError)
+		}
+	}
+
+	watcher.iMutex.Lock()
+	callbacks := append([]ChangeCallback{}, watcher.iCallbacks...)
+	watcher.iMutex.Unlock()
+
+	if err != nil {
+		watcher.reportError(err)
+		return err
+	}
+	if changes := Diff(watcher.iLive.Swap(configuration), configuration); len(changes) > 0 {
+		for _, callback := range callbacks {
+			callback(configuration, changes)
+		}
+	}
+	return nil
+}
+
+/*
+reportError call the error callbacks with err
+*/
+func (watcher *Watcher) reportError(err error) {
+
+	watcher.iMutex.Lock()
+	errorCallbacks := append([]func(err error){}, watcher.iErrorCallbacks...)
+	watcher.iMutex.Unlock()
+
+	for _, callback := range errorCallbacks {
+		callback(err)
+	}
+}
+
+/*
+Close stop watching the file
+*/
+func (watcher *Watcher) Close() error {
+
+	err := newError("eliteConfiguration.Watcher.Close()", errors.New("The Watcher is already closed"))
+	watcher.iClose.Do(func() {
+		close(watcher.iDone)
+		if err = watcher.iNotifier.Close(); err != nil {
+			err = newError("eliteConfiguration.Watcher.Close()", err)
+		}
+	})
+	return err
+}
+
+/*
+run reload the file once the writes stopped for the Debounce delay
+*/
+func (watcher *Watcher) run() {
+
+	timer := time.NewTimer(watcher.iOptions.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-watcher.iDone:
+			return
+		case _, open := <-watcher.iNotifier.Events():
+			if !open {
+				return
+			}
+			timer.Reset(watcher.iOptions.Debounce)
+		case <-timer.C:
+			watcher.Reload()
+		}
+	}
+}
+
+/*
+Diff return the Changes from the old to the new Configuration, the child Configurations being compared by property
+*/
+func Diff(oldConfiguration Configuration, newConfiguration Configuration) ChangeSet {
+
+	changes := ChangeSet{}
+	oldProperties, newProperties := map[string]Property{}, map[string]Property{}
+	flattenProperties(oldConfiguration, "", oldProperties)
+	flattenProperties(newConfiguration, "", newProperties)
+
+	for path, oldProperty := range oldProperties {
+		if newProperty, exist := newProperties[path]; !exist {
+			changes[path] = Change{Kind: ChangeRemoved, Old: oldProperty}
+		} else if !reflect.DeepEqual(oldProperty.Value(), newProperty.Value()) {
+			changes[path] = Change{Kind: ChangeModified, Old: oldProperty, New: newProperty}
+		}
+	}
+	for path, newProperty := range newProperties {
+		if _, exist := oldProperties[path]; !exist {
+			changes[path] = Change{Kind: ChangeAdded, New: newProperty}
+		}
+	}
+	return changes
+}
+
+/*
+flattenProperties add the properties of the configuration (but the child Configurations) by dotted path
+*/
+func flattenProperties(configuration Configuration, path string, properties map[string]Property) {
+
+	if configuration == nil {
+		return
+	}
+	for _, key := range configuration.Keys() {
+		propertyPath := key
+		if path != "" {
+			propertyPath = path + PathSeparator + key
+		}
+		property := configuration.Property(key)
+		if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+			flattenProperties(child, propertyPath, properties)
+			continue
+		}
+		properties[propertyPath] = property
+	}
+}
+
+/*
+pollingNotifier send an event when the modification time or the size of the file changes
+*/
+type pollingNotifier struct {
+	iEvents chan struct{}
+	iDone   chan struct{}
+	iClose  sync.Once
+}
+
+/*
+newPollingNotifier return a fileNotifier checking the file every interval
+*/
+func newPollingNotifier(fileName string, interval time.Duration) fileNotifier {
+
+	notifier := &pollingNotifier{iEvents: make(chan struct{}, 1), iDone: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastInfo, _ := os.Stat(fileName)
+		for {
+			select {
+			case <-notifier.iDone:
+				return
+			case <-ticker.C:
+				info, _ := os.Stat(fileName)
+				if (info == nil) != (lastInfo == nil) || info != nil && (!info.ModTime().Equal(lastInfo.ModTime()) || info.Size() != lastInfo.Size()) {
+					notify(notifier.iEvents)
+				}
+				lastInfo = info
+			}
+		}
+	}()
+	return notifier
+}
+
+/*
+Events return the channel of the events
+*/
+func (notifier *pollingNotifier) Events() <-chan struct{} {
+	return notifier.iEvents
+}
+
+/*
+Close stop the polling
+*/
+func (notifier *pollingNotifier) Close() error {
+
+	notifier.iClose.Do(func() { close(notifier.iDone) })
+	return nil
+}
+
+/*
+notify send an event without blocking, a pending event being enough
+*/
+func notify(events chan struct{}) {
+
+	select {
+	case events <- struct{}{}:
+	default:
+	}
+}
+
+/*
+watchedName return the absolute directory and the base name of the file
+*/
+func watchedName(fileName string) (string, string, error) {
+
+	absolutePath, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Dir(absolutePath), filepath.Base(absolutePath), nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/*
+Strategies of Merge for the arrays (ArrayReplace by default) and for the values of conflicting types
+(ConflictOverlay by default)
+*/
+const (
+	ArrayReplace    = "replace"
+	ArrayAppend     = "append"
+	ArrayUnion      = "unique-union"
+	ConflictOverlay = "overlay"
+	ConflictError   = "error"
+)
+
+/*
+MergeStrategy tells how Merge combines two arrays (ArrayReplace, ArrayAppend or ArrayUnion) and two values of
+conflicting types (ConflictOverlay or ConflictError). An empty field uses the global strategy
+*/
+type MergeStrategy struct {
+	Arrays    string
+	Conflicts string
+}
+
+/*
+MergeOptions configure Merge with global strategies, and strategies by dotted path in Keys which also apply to the
+properties under this path ({"servers": {Arrays: ArrayUnion}, "database": {Conflicts: ConflictError}})
+*/
+type MergeOptions struct {
+	Arrays    string
+	Conflicts string
+	Keys      map[string]MergeStrategy
+}
+
+/*
+Merge return a new Configuration (of the base kind) deep merging the overlay into the base: child Configurations
+are merged property by property, arrays are combined with the Arrays strategy and the other values of the overlay
+win. Values of conflicting types (an object and a string, a number and an array, ...) are replaced by the overlay
+or return an error with the ConflictError strategy. The properties keep the Source of the value which wins.
+Unlike the Merge method of Configuration, which replaces whole properties, it merges the values
+*/
+func Merge(base Configuration, overlay Configuration, options MergeOptions) (Configuration, error) {
+
+	if err := checkMergeOptions(options); err != nil {
+		return nil, err
+	}
+	return mergeConfigurations(mergeTarget(base), base, overlay, "", options)
+}
+
+/*
+mergeTarget return the empty Configuration (of the base kind) receiving the merge. A concurrent Configuration gets
+its own mutex instead of sharing the one of the base like its children
+*/
+func mergeTarget(base Configuration) Configuration {
+
+	target := base.newChild(base.Name())
+	if _, isConcurrent := target.(*concurrentConfiguration); isConcurrent {
+		return newConcurrentConfiguration(base.Name())
+	}
+	return target
+}
+
+/*
+checkMergeOptions return an error if a strategy is unknown
+*/
+func checkMergeOptions(options MergeOptions) error {
+
+	strategies := map[string]MergeStrategy{"": {Arrays: options.Arrays, Conflicts: options.Conflicts}}
+	for path, strategy := range options.Keys {
+		strategies[path] = strategy
+	}
+	for path, strategy := range strategies {
+		switch strategy.Arrays {
+		case "", ArrayReplace, ArrayAppend, ArrayUnion:
+		default:
+			return newError("eliteConfiguration.Merge()", fmt.Errorf("unknown arrays strategy %q for %q", strategy.Arrays, path))
+		}
+		switch strategy.Conflicts {
+		case "", ConflictOverlay, ConflictError:
+		default:
+			return newError("eliteConfiguration.Merge()", fmt.Errorf("unknown conflicts strategy %q for %q", strategy.Conflicts, path))
+		}
+	}
+	return nil
+}
+
+/*
+mergeConfigurations merge the base and the overlay found at path into merged, an empty Configuration
+*/
+func mergeConfigurations(merged Configuration, base Configuration, overlay Configuration, path string, options MergeOptions) (Configuration, error) {
+
+	keys := base.Keys()
+	for _, key := range overlay.Keys() {
+		if _, inBase := base.ownProperty(key); !inBase {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		propertyPath := key
+		if path != "" {
+			propertyPath = path + PathSeparator + key
+		}
+
+		var value interface{}
+		var source Source
+		var mergedChild bool
+		baseProperty, inBase := base.ownProperty(key)
+		overlayProperty, inOverlay := overlay.ownProperty(key)
+		switch {
+
+		case !inOverlay:
+			value, source = baseProperty.Value(), baseProperty.Source()
+
+		case !inBase:
+			value, source = overlayProperty.Value(), overlayProperty.Source()
+
+		default:
+			mergedValue, isMergedChild, err := mergeValues(merged.newChild(key), baseProperty.Value(), overlayProperty.Value(), propertyPath, options)
+			if err != nil {
+				return nil, err
+			}
+			value, source, mergedChild = mergedValue, overlayProperty.Source(), isMergedChild
+			if mergedChild {
+				source = baseProperty.Source()
+			}
+		}
+
+		// The child Configurations of only one side are copied, so the merged Configuration shares nothing with them
+		if child, isConfiguration := value.(Configuration); isConfiguration && !mergedChild {
+			copied, err := copiedConfiguration(merged.newChild(key), child, propertyPath, options)
+			if err != nil {
+				return nil, err
+			}
+			value = copied
+		}
+
+		merged = merged.AddProperty(merged.newProperty(key, value, false).WithSource(source))
+	}
+	return merged, nil
+}
+
+/*
+copiedConfiguration return a copy of the configuration found at path filling target, an empty Configuration
+*/
+func copiedConfiguration(target Configuration, configuration Configuration, path string, options MergeOptions) (Configuration, error) {
+	return mergeConfigurations(target, target.newChild(target.Name()), configuration, path, options)
+}
+
+/*
+mergeValues return the merged value of the base and overlay values found at path, and whether it's a merged child
+Configuration (keeping the Source of the base) filling target, an empty Configuration
+*/
+func mergeValues(target Configuration, baseValue interface{}, overlayValue interface{}, path string, options MergeOptions) (interface{}, bool, error) {
+
+	strategy := mergeStrategy(path, options)
+	baseKind, overlayKind := mergeKind(baseValue), mergeKind(overlayValue)
+
+	switch {
+	case baseKind == "object" && overlayKind == "object":
+		value, err := mergeConfigurations(target, baseValue.(Configuration), overlayValue.(Configuration), path, options)
+		return value, true, err
+
+	case baseKind == "array" && overlayKind == "array":
+		return mergeArrays(baseValue, overlayValue, strategy.Arrays), false, nil
+
+	case baseKind != overlayKind && baseKind != "null" && overlayKind != "null" && strategy.Conflicts == ConflictError:
+		return nil, false, newError("eliteConfiguration.Merge()", fmt.Errorf("%v: %v value can't be merged with %v value", path, baseKind, overlayKind))
+	}
+	return overlayValue, false, nil
+}
+
+/*
+mergeStrategy return the strategy of the path: the one of the nearest path in options.Keys, completed by the global
+strategies and the defaults
+*/
+func mergeStrategy(path string, options MergeOptions) MergeStrategy {
+
+	strategy := MergeStrategy{}
+	for current := path; current != "" && (strategy.Arrays == "" || strategy.Conflicts == ""); {
+		if keyStrategy, exist := options.Keys[current]; exist {
+			if strategy.Arrays == "" {
+				strategy.Arrays = keyStrategy.Arrays
+			}
+			if strategy.Conflicts == "" {
+				strategy.Conflicts = keyStrategy.Conflicts
+			}
+		}
+		if index := strings.LastIndex(current, PathSeparator); index >= 0 {
+			current = current[:index]
+		} else {
+			current = ""
+		}
+	}
+
+	for _, field := range []struct {
+		value        *string
+		global       string
+		defaultValue string
+	}{{&strategy.Arrays, options.Arrays, ArrayReplace}, {&strategy.Conflicts, options.Conflicts, ConflictOverlay}} {
+		if *field.value == "" {
+			*field.value = field.global
+		}
+		if *field.value == "" {
+			*field.value = field.defaultValue
+		}
+	}
+	return strategy
+}
+
+/*
+mergeKind return the JSON kind of a value: "object", "array", "string", "number", "boolean" or "null"
+*/
+func mergeKind(value interface{}) string {
+
+	switch value.(type) {
+	case nil:
+		return "null"
+	case Configuration:
+		return "object"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case time.Duration:
+		return "duration"
+	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "number"
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+/*
+mergeArrays combine the base and overlay arrays with the strategy. The result is a []interface{} unless the overlay
+replaces the base
+*/
+func mergeArrays(baseValue interface{}, overlayValue interface{}, strategy string) interface{} {
+
+	if strategy == ArrayReplace {
+		return overlayValue
+	}
+
+	baseItems, overlayItems := reflect.ValueOf(baseValue), reflect.ValueOf(overlayValue)
+	items := make([]interface{}, 0, baseItems.Len()+overlayItems.Len())
+	for _, array := range []reflect.Value{baseItems, overlayItems} {
+		for index := 0; index < array.Len(); index++ {
+			item := array.Index(index).Interface()
+			if strategy == ArrayUnion && containsItem(items, item) {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+/*
+containsItem check if items contains an item deeply equal to item, the numbers being compared by value (1 equals 1.0)
+*/
+func containsItem(items []interface{}, item interface{}) bool {
+
+	number, isNumber := toFloat(item)
+	isNumber = isNumber && mergeKind(item) == "number"
+	for _, existing := range items {
+		if isNumber && mergeKind(existing) == "number" {
+			if existingNumber, _ := toFloat(existing); existingNumber == number {
+				return true
+			}
+		} else if reflect.DeepEqual(existing, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+/*
+DotEnvCodec reads and writes dotenv files (KEY=VALUE lines): the keys are kept as they are when loading
+and the values are read as strings (the typed accessors convert them). Values can be single quoted (literal),
+double quoted (with \n, \t, \", \\ and \$ escapes, possibly on several lines) or unquoted (ended by a " #" comment).
+Variables ($OTHER) are not expanded.
+When saving, child Configurations are flattened: "database.pool.size" is written DATABASE_POOL_SIZE
+*/
+type DotEnvCodec struct {
+}
+
+var (
+	dotEnvKeyPattern   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+	dotEnvPlainPattern = regexp.MustCompile(`^[A-Za-z0-9_./:,@%+=\-]*$`)
+)
+
+/*
+Decode the dotenv content into the configuration
+*/
+func (codec DotEnvCodec) Decode(content []byte, configuration Configuration) (Configuration, error) {
+
+	// Read the variables in the file's order, the last duplicated variable wins
+	variables := newOrderedObject()
+	lines := strings.Split(strings.TrimPrefix(string(content), "\ufeff"), "\n")
+	for index := 0; index < len(lines); index++ {
+
+		number := index + 1
+		text := strings.TrimSpace(lines[index])
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// Optional shell "export" prefix
+		if strings.HasPrefix(text, "export") && len(text) > len("export") && unicode.IsSpace(rune(text[len("export")])) {
+			text = strings.TrimSpace(text[len("export"):])
+		}
+
+		separator := strings.Index(text, "=")
+		if separator < 0 {
+			return nil, newDotEnvError(number, "expected KEY=VALUE")
+		}
+		key := strings.TrimSpace(text[:separator])
+		if !dotEnvKeyPattern.MatchString(key) {
+			return nil, newDotEnvError(number, "invalid key "+key)
+		}
+		rawValue := strings.TrimLeft(text[separator+1:], " \t")
+
+		var value string
+		switch {
+
+		case strings.HasPrefix(rawValue, "\"") || strings.HasPrefix(rawValue, "'"):
+
+			// Quoted values can continue on the next lines until their closing quote
+			quote := rawValue[0]
+			closing := closingDotEnvQuote(rawValue, quote)
+			for closing < 0 && index+1 < len(lines) {
+				index++
+				rawValue += "\n" + strings.TrimSuffix(lines[index], "\r")
+				closing = closingDotEnvQuote(rawValue, quote)
+			}
+			if closing < 0 {
+				return nil, newDotEnvError(number, "unclosed quoted value")
+			}
+			if rest := strings.TrimSpace(rawValue[closing+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return nil, newDotEnvError(number, "unexpected content after the quoted value")
+			}
+			value = rawValue[1:closing]
+			if quote == '"' {
+				value = unescapeDotEnv(value)
+			}
+
+		default:
+			value = rawValue
+			if comment := strings.Index(value, " #"); comment >= 0 {
+				value = value[:comment]
+			} else if comment := strings.Index(value, "\t#"); comment >= 0 {
+				value = value[:comment]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		variables.set(key, value)
+		variables.locate(key, number, len(lines[number-1])-len(strings.TrimLeft(lines[number-1], " \t"))+1)
+	}
+	return fromObject(variables, configuration), nil
+}
+
+/*
+newDotEnvError return a new configurationError located at the line number
+*/
+func newDotEnvError(number int, message string) error {
+	return newError("eliteConfiguration.DotEnvCodec.Decode()", fmt.Errorf("line %v: %v", number, message))
+}
+
+/*
+closingDotEnvQuote return the index of the quote closing the value (starting with the quote), -1 if not found
+*/
+func closingDotEnvQuote(text string, quote byte) int {
+
+	for index := 1; index < len(text); index++ {
+		switch {
+		case text[index] == '\\' && quote == '"':
+			index++
+		case text[index] == quote:
+			return index
+		}
+	}
+	return -1
+}
+
+/*
+unescapeDotEnv replace the escape sequences of a double quoted value
+*/
+func unescapeDotEnv(text string) string {
+
+	replacer := strings.NewReplacer("\\n", "\n", "\\r", "\r", "\\t", "\t", "\\\"", "\"", "\\\\", "\\", "\\$", "$", "\\`", "`")
+	return replacer.Replace(text)
+}
+
+/*
+Encode the configuration in the dotenv format, child Configurations are flattened in upper case names
+*/
+func (codec DotEnvCodec) Encode(configuration Configuration) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	if err := writeDotEnv(&buffer, configuration, "", make(map[string]bool)); err != nil {
+		return nil, newError("eliteConfiguration.DotEnvCodec.Encode()", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+writeDotEnv write the properties of the configuration (and its children) with names prefixed by the prefix, but the
+RootPath which isn't a variable of the application.
+written keeps the names already written to detect the properties written with the same name
+*/
+func writeDotEnv(buffer *bytes.Buffer, configuration Configuration, prefix string, written map[string]bool) error {
+
+	for _, key := range configuration.Keys() {
+		if prefix == "" && key == RootPathKey {
+			continue
+		}
+		value := configuration.Property(key).Value()
+		name := prefix + dotEnvName(key)
+		if child, isConfiguration := value.(Configuration); isConfiguration {
+			if err := writeDotEnv(buffer, child, name+"_", written); err != nil {
+				return err
+			}
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if written[name] {
+			return fmt.Errorf("%v is used by several properties", name)
+		}
+		written[name] = true
+
+		text, err := flatText(value)
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+		buffer.WriteString(name + "=" + quoteDotEnv(text) + "\n")
+	}
+	return nil
+}
+
+/*
+dotEnvName return the upper case variable name of a Configuration key, the invalid characters become '_'
+*/
+func dotEnvName(key string) string {
+
+	return strings.Map(func(character rune) rune {
+		switch {
+		case character >= 'a' && character <= 'z':
+			return unicode.ToUpper(character)
+		case character >= 'A' && character <= 'Z', character >= '0' && character <= '9':
+			return character
+		}
+		return '_'
+	}, key)
+}
+
+/*
+quoteDotEnv return the text, double quoted and escaped if it contains characters a shell would interpret
+*/
+func quoteDotEnv(text string) string {
+
+	if dotEnvPlainPattern.MatchString(text) {
+		return text
+	}
+	replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t", "$", "\\$", "`", "\\`")
+	return "\"" + replacer.Replace(text) + "\""
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+EnvironmentOptions configure how the environment variables are mapped to the properties' names.
+A variable is named with its Prefix, then the names of the property's path in upper case joined by the Separator
+("_" by default): APP_DATABASE_POOL_SIZE is database.pool.size with the "APP" Prefix.
+Mapping gives explicit names for some variables ({"DB_URL": "database.url"}) and Environ replaces os.Environ()
+*/
+type EnvironmentOptions struct {
+	Prefix    string
+	Separator string
+	Mapping   map[string]string
+	Environ   []string
+}
+
+/*
+overlayEnvironment return a Layered Configuration with the environment variables over the configuration.
+The variables of the existing properties are converted to the type of their value, the other prefixed variables
+are added as strings. A variable naming a child Configuration returns an error. Without Prefix only the mapped
+variables are used, so that the unrelated variables of the process (PATH, HOME, USER...) never replace a property.
+RootPath is never overlaid
+*/
+func overlayEnvironment(configuration Configuration, options EnvironmentOptions, createNew func(requiredName string) Configuration) (Configuration, error) {
+
+	separator := options.Separator
+	if separator == "" {
+		separator = "_"
+	}
+	prefix := ""
+	if options.Prefix != "" {
+		prefix = strings.ToUpper(options.Prefix) + separator
+	}
+	environ := options.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+
+	// Index the existing properties by their variable name, only prefixed variables are matched
+	existing := make(map[string]string)
+	if prefix != "" {
+		indexEnvironmentNames(configuration, "", prefix, separator, existing)
+	}
+
+	variables := make(map[string]string)
+	for _, variable := range environ {
+		if index := strings.Index(variable, "="); index > 0 {
+			variables[variable[:index]] = variable[index+1:]
+		}
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	overlay := createNew(configuration.Name())
+	var messages []string
+	for _, name := range names {
+
+		path, mapped := options.Mapping[name]
+		if !mapped {
+			if path, mapped = existing[strings.ToUpper(name)]; !mapped && prefix != "" && strings.HasPrefix(strings.ToUpper(name), prefix) && len(name) > len(prefix) {
+				path, mapped = strings.ToLower(strings.Replace(name[len(prefix):], separator, PathSeparator, -1)), true
+			}
+		}
+		if !mapped || strings.EqualFold(path, RootPathKey) {
+			continue
+		}
+
+		value, err := environmentValue(variables[name], configuration.Property(path).Value())
+		if err != nil {
+			messages = append(messages, name+": "+err.Error())
+			continue
+		}
+		overlay = overlay.Add(path, value)
+		overlay = withPathSource(overlay, path, Source{Kind: SourceEnvironment, Name: name})
+	}
+
+	if len(messages) > 0 {
+		return nil, newError("eliteConfiguration.OverlayEnvironment()", errors.New(strings.Join(messages, "\n")))
+	}
+	return Layered(configuration, overlay), nil
+}
+
+/*
+indexEnvironmentNames add the variable names of all the configuration's properties (but the child Configurations)
+to names
+*/
+func indexEnvironmentNames(configuration Configuration, path string, prefix string, separator string, names map[string]string) {
+
+	for _, key := range configuration.Keys() {
+		if path == "" && key == RootPathKey {
+			continue
+		}
+		propertyPath, variable := key, prefix+dotEnvName(key)
+		if path != "" {
+			propertyPath = path + PathSeparator + key
+		}
+		if child, isConfiguration := configuration.Property(key).Value().(Configuration); isConfiguration {
+			indexEnvironmentNames(child, propertyPath, variable+separator, separator, names)
+			continue
+		}
+		names[variable] = propertyPath
+	}
+}
+
+/*
+environmentValue convert the text of a variable to the type of the existing value, a child Configuration can't be
+replaced by a variable
+*/
+func environmentValue(text string, existingValue interface{}) (interface{}, error) {
+
+	var value interface{}
+	var converted bool
+	switch existingValue.(type) {
+	case nil, string:
+		return text, nil
+	case Configuration:
+		return nil, fmt.Errorf("value %q can't replace a child Configuration", text)
+	case bool:
+		value, converted = toBool(text)
+	case float32, float64, json.Number:
+		value, converted = toFloat(text)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		value, converted = toInt(text)
+	case time.Duration:
+		value, converted = toDuration(text)
+	case []string:
+		value, converted = toStringSlice(text)
+	case []interface{}, map[string]interface{}:
+
+		// Arrays (and objects) can be written in JSON, arrays also as comma separated lists
+		if err := json.Unmarshal([]byte(text), &value); err == nil {
+			return value, nil
+		}
+		if items, isList := toStringSlice(text); isList {
+			if _, isArray := existingValue.([]interface{}); isArray {
+				list := make([]interface{}, len(items))
+				for index, item := range items {
+					list[index] = item
+				}
+				return list, nil
+			}
+		}
+	default:
+		return text, nil
+	}
+	if !converted {
+		return nil, fmt.Errorf("value %q can't be converted to %T", text, existingValue)
+	}
+	return value, nil
+}
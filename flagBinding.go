@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"flag"
+	"fmt"
+)
+
+/*
+configurationFlag is the flag.Value of a Property, the flag's text is converted to the type of the Property's Value
+*/
+type configurationFlag struct {
+	iValue interface{}
+}
+
+/*
+String return the text of the flag's Value
+*/
+func (value *configurationFlag) String() string {
+
+	if value == nil {
+		return ""
+	}
+	if text, err := flatText(value.iValue); err == nil {
+		return text
+	}
+	return fmt.Sprint(value.iValue)
+}
+
+/*
+Set convert the text to the type of the flag's Value
+*/
+func (value *configurationFlag) Set(text string) error {
+
+	converted, err := environmentValue(text, value.iValue)
+	if err != nil {
+		return err
+	}
+	value.iValue = converted
+	return nil
+}
+
+/*
+Get return the flag's Value
+*/
+func (value *configurationFlag) Get() interface{} {
+	return value.iValue
+}
+
+/*
+IsBoolFlag allows boolean flags without value (-debug)
+*/
+func (value *configurationFlag) IsBoolFlag() bool {
+
+	_, isBool := value.iValue.(bool)
+	return isBool
+}
+
+/*
+FlagSet return a new flag.FlagSet with one flag per Property of the configuration, named with its dotted path
+(-database.pool.size=20) and typed by its current Value which is also the flag's default
+*/
+func FlagSet(configuration Configuration, requiredName string, errorHandling flag.ErrorHandling) *flag.FlagSet {
+
+	flags := flag.NewFlagSet(requiredName, errorHandling)
+	defineFlags(flags, configuration, configuration, "")
+	return flags
+}
+
+/*
+defineFlags define the flags of the configuration's properties, child Configurations are walked with their path.
+A literal dotted key and a nested path with the same name ({"database.host": ..., "database": {"host": ...}})
+define only one flag, holding the Property found by root.Property() where the literal key wins
+*/
+func defineFlags(flags *flag.FlagSet, root Configuration, configuration Configuration, path string) {
+
+	for _, key := range configuration.Keys() {
+		if path == "" && key == RootPathKey {
+			continue
+		}
+		propertyPath := key
+		if path != "" {
+			propertyPath = path + PathSeparator + key
+		}
+
+		if flags.Lookup(propertyPath) != nil {
+			continue
+		}
+
+		if child, isConfiguration := configuration.Property(key).Value().(Configuration); isConfiguration {
+			defineFlags(flags, root, child, propertyPath)
+			continue
+		}
+		property := root.Property(propertyPath)
+		if _, isConfiguration := property.Value().(Configuration); isConfiguration {
+			continue
+		}
+		flags.Var(&configurationFlag{iValue: property.Value()}, propertyPath, fmt.Sprintf("%v (from %v)", propertyPath, property.Source()))
+	}
+}
+
+/*
+ApplyFlags return a Layered Configuration with the flags set on the command line over the configuration.
+Only the flags defined by FlagSet and parsed are applied
+*/
+func ApplyFlags(configuration Configuration, flags *flag.FlagSet) Configuration {
+
+	overlay := configuration.newChild(configuration.Name())
+	flags.Visit(func(setFlag *flag.Flag) {
+		if value, isConfigurationFlag := setFlag.Value.(*configurationFlag); isConfigurationFlag {
+
+			// A literal dotted key must stay literal to win over the nested path of the lower layers
+			if _, isLiteral := configuration.ownProperty(setFlag.Name); isLiteral {
+				overlay = addLiteral(overlay, setFlag.Name, value.iValue)
+			} else {
+				overlay = overlay.Add(setFlag.Name, value.iValue)
+			}
+			overlay = withPathSource(overlay, setFlag.Name, Source{Kind: SourceFlag, Name: "-" + setFlag.Name})
+		}
+	})
+	return Layered(configuration, overlay)
+}
@@ -3,14 +3,17 @@ Copyright (c) 2016 EliteSystems. All rights reserved.
 */
 package eliteConfiguration
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 /*
 immutableConfiguration is an internal immutable Configuration struct
 */
 type immutableConfiguration struct {
 	iName       string
-	iProperties map[string]Property
+	iProperties propertyTrie
 }
 
 /*
@@ -34,8 +37,8 @@ Value return the raw(untyped) Value of a specified named Property. If Property d
 */
 func (configuration immutableConfiguration) Value(requiredName string) (interface{}, error) {
 
-	// Access to Property by its Name
-	if property, exist := configuration.iProperties[requiredName]; !exist {
+	// Access to Property by its Name or its dotted path
+	if property, exist := lookupProperty(configuration, requiredName); !exist {
 		return nil, newError("Configuration.Value(\""+requiredName+"\")", errors.New("Key not found"))
 	} else {
 		return property.Value(), nil
@@ -49,13 +52,168 @@ func (configuration immutableConfiguration) ValueWithDefault(requiredName string
 	return configuration.Property(requiredName).WithDefault(requiredDefaultValue).Value()
 }
 
+/*
+String return the Value of a specified named Property converted to string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) String(requiredName string) string {
+	value, _ := stringValue(configuration, requiredName)
+	return value
+}
+
+/*
+StringWithDefault return the Value of a specified named Property converted to string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) StringWithDefault(requiredName string, requiredDefaultValue string) string {
+	if value, err := stringValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+StringValue return the Value of a specified named Property converted to string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration immutableConfiguration) StringValue(requiredName string) (string, error) {
+	return stringValue(configuration, requiredName)
+}
+
+/*
+Int return the Value of a specified named Property converted to int, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) Int(requiredName string) int {
+	value, _ := intValue(configuration, requiredName)
+	return value
+}
+
+/*
+IntWithDefault return the Value of a specified named Property converted to int, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) IntWithDefault(requiredName string, requiredDefaultValue int) int {
+	if value, err := intValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+IntValue return the Value of a specified named Property converted to int. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration immutableConfiguration) IntValue(requiredName string) (int, error) {
+	return intValue(configuration, requiredName)
+}
+
+/*
+Bool return the Value of a specified named Property converted to bool, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) Bool(requiredName string) bool {
+	value, _ := boolValue(configuration, requiredName)
+	return value
+}
+
+/*
+BoolWithDefault return the Value of a specified named Property converted to bool, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) BoolWithDefault(requiredName string, requiredDefaultValue bool) bool {
+	if value, err := boolValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+BoolValue return the Value of a specified named Property converted to bool. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration immutableConfiguration) BoolValue(requiredName string) (bool, error) {
+	return boolValue(configuration, requiredName)
+}
+
+/*
+Float return the Value of a specified named Property converted to float64, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) Float(requiredName string) float64 {
+	value, _ := floatValue(configuration, requiredName)
+	return value
+}
+
+/*
+FloatWithDefault return the Value of a specified named Property converted to float64, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) FloatWithDefault(requiredName string, requiredDefaultValue float64) float64 {
+	if value, err := floatValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+FloatValue return the Value of a specified named Property converted to float64. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration immutableConfiguration) FloatValue(requiredName string) (float64, error) {
+	return floatValue(configuration, requiredName)
+}
+
+/*
+Duration return the Value of a specified named Property converted to time.Duration, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) Duration(requiredName string) time.Duration {
+	value, _ := durationValue(configuration, requiredName)
+	return value
+}
+
+/*
+DurationWithDefault return the Value of a specified named Property converted to time.Duration, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) DurationWithDefault(requiredName string, requiredDefaultValue time.Duration) time.Duration {
+	if value, err := durationValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+DurationValue return the Value of a specified named Property converted to time.Duration. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration immutableConfiguration) DurationValue(requiredName string) (time.Duration, error) {
+	return durationValue(configuration, requiredName)
+}
+
+/*
+StringSlice return the Value of a specified named Property converted to []string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) StringSlice(requiredName string) []string {
+	value, _ := stringSliceValue(configuration, requiredName)
+	return value
+}
+
+/*
+StringSliceWithDefault return the Value of a specified named Property converted to []string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration immutableConfiguration) StringSliceWithDefault(requiredName string, requiredDefaultValue []string) []string {
+	if value, err := stringSliceValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+StringSliceValue return the Value of a specified named Property converted to []string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration immutableConfiguration) StringSliceValue(requiredName string) ([]string, error) {
+	return stringSliceValue(configuration, requiredName)
+}
+
 /*
 Add a Property to the new Configuration returned
 */
 func (configuration immutableConfiguration) Add(requiredName string, optionalValue interface{}) Configuration {
 
+	// Add the Property into its child Configuration if the name is a dotted path
+	if returnConfiguration, added := addToChild(configuration, requiredName, optionalValue); added {
+		return returnConfiguration
+	}
+
 	var orphanFlag = false
-	return configuration.AddProperty(configuration.newProperty(requiredName, optionalValue, orphanFlag))
+	return configuration.AddProperty(configuration.newProperty(requiredName, childValue(configuration, requiredName, optionalValue), orphanFlag))
 
 }
 
@@ -64,27 +222,51 @@ Remove a property to the new Configuration returned
 */
 func (configuration immutableConfiguration) Remove(requiredName string) Configuration {
 
-	// Initialize a map copy and add it the new Property
-	mapCopy := make(map[string]Property)
-	if configuration.iProperties != nil {
-		for key, value := range configuration.iProperties {
-			if requiredName != key {
-				mapCopy[key] = value
-			}
-		}
+	// Remove the Property from its child Configuration if the name is a dotted path
+	if returnConfiguration, removed := removeFromChild(configuration, requiredName); removed {
+		return returnConfiguration
 	}
 
-	// Change the map of configuration with the copy
-	configuration.iProperties = mapCopy
-
+	// The trie copy shares all the properties but the path of the removed one
+	configuration.iProperties = configuration.iProperties.Without(requiredName)
 	return configuration
 }
 
+/*
+AddAll add the values (by name or dotted path) to the new Configuration returned, in the order of their sorted names
+*/
+func (configuration immutableConfiguration) AddAll(values map[string]interface{}) Configuration {
+	return addValues(configuration, values, addImmutableProperties)
+}
+
+/*
+RemoveAll remove the properties with the names (or dotted paths) from the new Configuration returned
+*/
+func (configuration immutableConfiguration) RemoveAll(requiredNames ...string) Configuration {
+	return removeNames(configuration, requiredNames, removeImmutableNames)
+}
+
+/*
+RemovePrefix remove the properties whose dotted path starts with the prefix ("database." or "cache_") from the
+new Configuration returned
+*/
+func (configuration immutableConfiguration) RemovePrefix(requiredPrefix string) Configuration {
+	return removePrefix(configuration, requiredPrefix, removeImmutableNames)
+}
+
+/*
+Merge add copies of all the properties of the configuration (keeping their Source) to the new Configuration returned, a
+property replacing the whole existing one
+*/
+func (configuration immutableConfiguration) Merge(requiredConfiguration Configuration) Configuration {
+	return addImmutableProperties(configuration, mergedProperties(requiredConfiguration, configuration))
+}
+
 /*
 Size return the size of the configuration (Number of properties)
 */
 func (configuration immutableConfiguration) Size() int {
-	return len(configuration.iProperties)
+	return configuration.iProperties.Size()
 }
 
 /*
@@ -92,8 +274,8 @@ Property always return a Property with the requiredName. The Configuration one i
 */
 func (configuration immutableConfiguration) Property(requiredName string) Property {
 
-	// Access to Property by its Name
-	if property, exist := configuration.iProperties[requiredName]; exist {
+	// Access to Property by its Name or its dotted path
+	if property, exist := lookupProperty(configuration, requiredName); exist {
 		return property
 	}
 
@@ -107,11 +289,9 @@ HasProperty check if the named Property exist or not in the Configuration
 */
 func (configuration immutableConfiguration) HasProperty(requiredName string) bool {
 
-	// Access to Property by its Name
-	if _, exist := configuration.iProperties[requiredName]; exist {
-		return true
-	}
-	return false
+	// Access to Property by its Name or its dotted path
+	_, exist := lookupProperty(configuration, requiredName)
+	return exist
 }
 
 /*
@@ -119,19 +299,37 @@ AddProperty add a Property to the Configuration returned
 */
 func (configuration immutableConfiguration) AddProperty(property Property) Configuration {
 
-	// Initialize a map copy and add it the new Property
-	mapCopy := make(map[string]Property)
-	if configuration.iProperties != nil {
-		for key, value := range configuration.iProperties {
-			mapCopy[key] = value
-		}
-	}
-	mapCopy[property.Name()] = property
+	// The trie copy shares all the properties but the path of the added one, a new name is kept at the end of the order
+	configuration.iProperties = configuration.iProperties.With(property)
+	return configuration
+}
 
-	// Change the map of configuration with the copy
-	configuration.iProperties = mapCopy
+/*
+Keys return the names of all the properties of the configuration in their insertion order
+*/
+func (configuration immutableConfiguration) Keys() []string {
+	return configuration.iProperties.Names()
+}
 
-	return configuration
+/*
+Explain return the Property with the requiredName (in a slice), or an empty slice if it doesn't exist
+*/
+func (configuration immutableConfiguration) Explain(requiredName string) []Property {
+	return explainProperty(configuration, requiredName)
+}
+
+/*
+Sub always return the child Configuration with the requiredPath. The Configuration one if exists, a new empty one else
+*/
+func (configuration immutableConfiguration) Sub(requiredPath string) Configuration {
+	return subConfiguration(configuration, requiredPath)
+}
+
+/*
+newChild instantiate and return an appropriate child Configuration
+*/
+func (configuration immutableConfiguration) newChild(requiredName string) Configuration {
+	return immutableConfiguration{iName: requiredName}
 }
 
 /*
@@ -144,8 +342,35 @@ func (configuration immutableConfiguration) newProperty(requiredName string, opt
 }
 
 /*
-properties return all the properties of the configuration
+ownProperty return the Property named exactly requiredName
+*/
+func (configuration immutableConfiguration) ownProperty(requiredName string) (Property, bool) {
+	return configuration.iProperties.Get(requiredName)
+}
+
+/*
+properties return a map of all the properties of the configuration
 */
 func (configuration immutableConfiguration) properties() map[string]Property {
-	return configuration.iProperties
+	return configuration.iProperties.Map()
+}
+
+/*
+addImmutableProperties add the properties to the trie of the configuration in one batch
+*/
+func addImmutableProperties(configuration Configuration, properties []Property) Configuration {
+
+	returnConfiguration := configuration.(immutableConfiguration)
+	returnConfiguration.iProperties = returnConfiguration.iProperties.WithAll(properties)
+	return returnConfiguration
+}
+
+/*
+removeImmutableNames remove the names from the trie of the configuration in one batch
+*/
+func removeImmutableNames(configuration Configuration, names []string) Configuration {
+
+	returnConfiguration := configuration.(immutableConfiguration)
+	returnConfiguration.iProperties = returnConfiguration.iProperties.WithoutAll(names)
+	return returnConfiguration
 }
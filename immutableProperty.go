@@ -10,6 +10,7 @@ type immutableProperty struct {
 	iName   string
 	iValue  interface{}
 	iOrphan bool
+	iSource Source
 }
 
 /*
@@ -35,3 +36,18 @@ func (property immutableProperty) WithDefault(requiredDefaultValue interface{})
 	}
 	return property
 }
+
+/*
+Source get where the Property comes from
+*/
+func (property immutableProperty) Source() Source {
+	return normalizedSource(property.iSource)
+}
+
+/*
+WithSource return a new Property with the source
+*/
+func (property immutableProperty) WithSource(requiredSource Source) Property {
+	property.iSource = requiredSource
+	return property
+}
@@ -3,6 +3,11 @@ Copyright (c) 2016 EliteSystems. All rights reserved.
 */
 package eliteConfiguration
 
+import (
+	"io"
+	"io/fs"
+)
+
 /*
 immutableState is the stateless API facade struct used to manipulate immutable Configurations
 */
@@ -18,7 +23,7 @@ func (state immutableState) New(requiredName string) Configuration {
 }
 
 /*
-Load fileName with valid JSON Content into a returned Configuration
+Load fileName into a returned Configuration, decoded by the Codec registered for its extension (JSON by default)
 */
 func (state immutableState) Load(fileName string) (Configuration, error) {
 
@@ -26,9 +31,92 @@ func (state immutableState) Load(fileName string) (Configuration, error) {
 }
 
 /*
-Save a Configuration to fileName in indented JSON format
+Save a Configuration to fileName, encoded by the Codec registered for its extension (indented JSON by default)
 */
 func (state immutableState) Save(configuration Configuration, fileName string) error {
 
 	return save(configuration, fileName)
 }
+
+/*
+LoadDotEnv fileName with KEY=VALUE lines (dotenv format, whatever its extension) into a returned Configuration
+*/
+func (state immutableState) LoadDotEnv(fileName string) (Configuration, error) {
+
+	return loadWith(fileName, DotEnvCodec{}, state.New)
+}
+
+/*
+SaveDotEnv a Configuration to fileName with KEY=VALUE lines (dotenv format, whatever its extension)
+*/
+func (state immutableState) SaveDotEnv(configuration Configuration, fileName string) error {
+
+	return saveWith(configuration, fileName, DotEnvCodec{})
+}
+
+/*
+LoadWith fileName into a returned Configuration, decoded by the codec whatever its extension (JSONCodec{Plain: true}
+to read a JSON file as a plain object even if it looks like the marshallableConfiguration format)
+*/
+func (state immutableState) LoadWith(fileName string, codec Codec) (Configuration, error) {
+
+	return loadWith(fileName, codec, state.New)
+}
+
+/*
+SaveWith a Configuration to fileName, encoded by the codec whatever its extension (JSONCodec{Preserve: true} to keep
+the comments and the layout of an existing file)
+*/
+func (state immutableState) SaveWith(configuration Configuration, fileName string, codec Codec) error {
+
+	return saveWith(configuration, fileName, codec)
+}
+
+/*
+LoadFrom reader with JSON Content (decoded by JSONCodec, whatever the registered Codecs) into a returned Configuration
+*/
+func (state immutableState) LoadFrom(reader io.Reader) (Configuration, error) {
+
+	return loadFrom(reader, state.New)
+}
+
+/*
+SaveTo a Configuration to writer in indented JSON format (encoded by JSONCodec, whatever the registered Codecs)
+*/
+func (state immutableState) SaveTo(configuration Configuration, writer io.Writer) error {
+
+	return saveTo(configuration, writer)
+}
+
+/*
+Parse JSON Content (decoded by JSONCodec, whatever the registered Codecs) into a returned Configuration
+*/
+func (state immutableState) Parse(content []byte) (Configuration, error) {
+
+	return parse(content, state.New)
+}
+
+/*
+Marshal a Configuration in indented JSON format (encoded by JSONCodec, whatever the registered Codecs)
+*/
+func (state immutableState) Marshal(configuration Configuration) ([]byte, error) {
+
+	return marshal(configuration)
+}
+
+/*
+LoadFS the file name of fsys (embed.FS, os.DirFS, ...) into a returned Configuration, with a RootPath relative to fsys
+*/
+func (state immutableState) LoadFS(fsys fs.FS, name string) (Configuration, error) {
+
+	return loadFS(fsys, name, state.New)
+}
+
+/*
+OverlayEnvironment return a Layered Configuration with the environment variables selected by options over the
+configuration, converted to the type of the values they replace
+*/
+func (state immutableState) OverlayEnvironment(configuration Configuration, options EnvironmentOptions) (Configuration, error) {
+
+	return overlayEnvironment(configuration, options, state.New)
+}
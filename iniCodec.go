@@ -0,0 +1,249 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+INICodec reads and writes INI files: the keys before the first section are the Configuration's properties and
+each section ("[database]", "[database.pool]") becomes a child Configuration. Values are read as strings
+(the typed accessors convert them) and the Configuration's Name is not stored. A key defined twice in the same
+section is an error
+*/
+type INICodec struct {
+}
+
+/*
+Decode the INI content into the configuration
+*/
+func (codec INICodec) Decode(content []byte, configuration Configuration) (Configuration, error) {
+
+	root := newOrderedObject()
+	section := root
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+
+		text := strings.TrimSpace(scanner.Text())
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// Section header: walk (or create) the child objects of its dotted name
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, newINIError(number, "unclosed section header")
+			}
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if name == "" {
+				return nil, newINIError(number, "empty section name")
+			}
+			walked, err := walkINISection(root, strings.Split(name, PathSeparator))
+			if err != nil {
+				return nil, newINIError(number, err.Error())
+			}
+			section = walked
+			continue
+		}
+
+		// Key/value pair separated by the first '=' or ':'
+		index := strings.IndexAny(text, "=:")
+		if index <= 0 {
+			return nil, newINIError(number, "expected key = value")
+		}
+		key := strings.TrimSpace(text[:index])
+		if value, exist := section.values[key]; exist {
+			if _, isSection := value.(*orderedObject); isSection {
+				return nil, newINIError(number, "key "+key+" is already used by a section")
+			}
+			return nil, newINIError(number, "duplicated key "+key)
+		}
+		section.set(key, unquoteINI(strings.TrimSpace(text[index+1:])))
+		section.locate(key, number, len(scanner.Text())-len(strings.TrimLeft(scanner.Text(), " \t"))+1)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newError("eliteConfiguration.INICodec.Decode()", err)
+	}
+	return fromObject(root, configuration), nil
+}
+
+/*
+newINIError return a new configurationError located at the line number
+*/
+func newINIError(number int, message string) error {
+	return newError("eliteConfiguration.INICodec.Decode()", fmt.Errorf("line %v: %v", number, message))
+}
+
+/*
+walkINISection return the object designated by the names, creating the missing ones
+*/
+func walkINISection(object *orderedObject, names []string) (*orderedObject, error) {
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("empty name in section header")
+		}
+		value, exist := object.values[name]
+		if !exist {
+			value = newOrderedObject()
+			object.set(name, value)
+		}
+		child, isObject := value.(*orderedObject)
+		if !isObject {
+			return nil, errors.New("section " + name + " is already used by a key")
+		}
+		object = child
+	}
+	return object, nil
+}
+
+/*
+unquoteINI remove the matching single or double quotes around a value
+*/
+func unquoteINI(text string) string {
+
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
+
+/*
+Encode the configuration in INI, child Configurations become sections
+*/
+func (codec INICodec) Encode(configuration Configuration) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	if err := writeINISection(&buffer, configuration, nil); err != nil {
+		return nil, newError("eliteConfiguration.INICodec.Encode()", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+writeINISection write the key/value pairs of the configuration then its child Configurations as sections
+*/
+func writeINISection(buffer *bytes.Buffer, configuration Configuration, path []string) error {
+
+	var children []string
+	for _, key := range configuration.Keys() {
+		value := configuration.Property(key).Value()
+		if _, isConfiguration := value.(Configuration); isConfiguration {
+			children = append(children, key)
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		text, err := flatText(value)
+		if err != nil {
+			return fmt.Errorf("%v: %v", strings.Join(append(path, key), PathSeparator), err)
+		}
+		if strings.ContainsAny(key, "=:[;#\r\n") || key != strings.TrimSpace(key) || key == "" {
+			return fmt.Errorf("%v: the key can't be written in INI", strings.Join(append(path, key), PathSeparator))
+		}
+		if strings.ContainsAny(text, "\r\n") {
+			return fmt.Errorf("%v: multiline values can't be written in INI", strings.Join(append(path, key), PathSeparator))
+		}
+		buffer.WriteString(key + " = " + quoteINI(text) + "\n")
+	}
+
+	for _, key := range children {
+		childPath := append(append([]string{}, path...), key)
+		child := configuration.Property(key).Value().(Configuration)
+		if strings.ContainsAny(key, PathSeparator+"[]\r\n") || key != strings.TrimSpace(key) || key == "" {
+			return fmt.Errorf("%v: the section name can't be written in INI", strings.Join(childPath, PathSeparator))
+		}
+
+		// A section only holding sections doesn't need its own header
+		if hasScalarProperty(child) || len(child.Keys()) == 0 {
+			if buffer.Len() > 0 {
+				buffer.WriteString("\n")
+			}
+			buffer.WriteString("[" + strings.Join(childPath, PathSeparator) + "]\n")
+		}
+		if err := writeINISection(buffer, child, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+hasScalarProperty check if the configuration has a property which isn't a child Configuration
+*/
+func hasScalarProperty(configuration Configuration) bool {
+
+	for _, key := range configuration.Keys() {
+		if _, isConfiguration := configuration.Property(key).Value().(Configuration); !isConfiguration {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+quoteINI return the text, double quoted if reading it back would change it
+*/
+func quoteINI(text string) string {
+
+	if text != strings.TrimSpace(text) || unquoteINI(text) != text {
+		return "\"" + text + "\""
+	}
+	return text
+}
+
+/*
+flatText return the text of a scalar value for the flat formats (INI, properties).
+Arrays become comma separated lists, as read back by StringSlice
+*/
+func flatText(value interface{}) (string, error) {
+
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, nil
+	case bool:
+		return strconv.FormatBool(typedValue), nil
+	case float32, float64:
+		floatValue, _ := toFloat(typedValue)
+		return strconv.FormatFloat(floatValue, 'g', -1, 64), nil
+	case time.Duration:
+		return typedValue.String(), nil
+	case Configuration, map[string]interface{}:
+		return "", fmt.Errorf("value %v (%T) can't be written in a flat format", value, value)
+	case fmt.Stringer:
+		return typedValue.String(), nil
+	}
+
+	if items, isSequence := toYAMLSequence(value); isSequence {
+		texts := make([]string, len(items))
+		for index, item := range items {
+			text, err := flatText(item)
+			if err != nil {
+				return "", err
+			}
+			if _, isSequence := toYAMLSequence(item); isSequence || strings.Contains(text, ",") {
+				return "", fmt.Errorf("item %v can't be written in a comma separated list", item)
+			}
+			texts[index] = text
+		}
+		return strings.Join(texts, ","), nil
+	}
+	if intValue, isInt := toInt(value); isInt {
+		return strconv.Itoa(intValue), nil
+	}
+	return "", fmt.Errorf("value %v (%T) can't be written in a flat format", value, value)
+}
@@ -0,0 +1,90 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+/*
+inotifyNotifier send an event when the file is written, created, renamed or removed. The directory is watched so
+the files replaced by the editors (written elsewhere then renamed) are seen
+*/
+type inotifyNotifier struct {
+	iFile   *os.File
+	iEvents chan struct{}
+}
+
+/*
+newSystemNotifier return an inotify fileNotifier
+*/
+func newSystemNotifier(fileName string) (fileNotifier, error) {
+
+	directory, name, err := watchedName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, newError("syscall.InotifyInit1()", err)
+	}
+	mask := uint32(syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_CREATE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE)
+	if _, err := syscall.InotifyAddWatch(descriptor, directory, mask); err != nil {
+		syscall.Close(descriptor)
+		return nil, newError("syscall.InotifyAddWatch("+directory+")", err)
+	}
+
+	// The non blocking descriptor is handled by the runtime poller, so Close interrupts the pending Read
+	notifier := &inotifyNotifier{iFile: os.NewFile(uintptr(descriptor), "inotify"), iEvents: make(chan struct{}, 1)}
+	go notifier.read(name)
+	return notifier, nil
+}
+
+/*
+read the inotify events until the notifier is closed and notify the ones of the file name
+*/
+func (notifier *inotifyNotifier) read(name string) {
+
+	defer close(notifier.iEvents)
+	buffer := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		length, err := notifier.iFile.Read(buffer)
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= length; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			nameBytes := buffer[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			// The name is padded with NUL bytes
+			for len(nameBytes) > 0 && nameBytes[len(nameBytes)-1] == 0 {
+				nameBytes = nameBytes[:len(nameBytes)-1]
+			}
+			if string(nameBytes) == name {
+				notify(notifier.iEvents)
+			}
+		}
+	}
+}
+
+/*
+Events return the channel of the events
+*/
+func (notifier *inotifyNotifier) Events() <-chan struct{} {
+	return notifier.iEvents
+}
+
+/*
+Close stop watching, the events channel is then closed
+*/
+func (notifier *inotifyNotifier) Close() error {
+	return notifier.iFile.Close()
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"errors"
+)
+
+/*
+newSystemNotifier return an error, the Watcher then polls the file
+*/
+func newSystemNotifier(fileName string) (fileNotifier, error) {
+	return nil, newError("eliteConfiguration.newSystemNotifier("+fileName+")", errors.New("file notifications aren't supported on this system"))
+}
@@ -0,0 +1,671 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/*
+jsonNode is a value of a JSON document with its position in the document's content.
+The members of an object are kept in the document's order
+*/
+type jsonNode struct {
+	start   int
+	end     int
+	members []*jsonMember
+}
+
+/*
+jsonMember is a key/value pair of a JSON object. comma is the position of the comma following the value (-1 if none)
+*/
+type jsonMember struct {
+	key   string
+	start int
+	value *jsonNode
+	comma int
+}
+
+/*
+jsonEdit replace the content between start and end by text
+*/
+type jsonEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+/*
+jsonDocument is the internal state used to locate the values of a (relaxed) JSON document
+*/
+type jsonDocument struct {
+	content  []byte
+	position int
+	edits    []jsonEdit
+}
+
+/*
+encode return the content of the configuration for fileName. A DocumentCodec patches the existing file
+*/
+func encode(codec Codec, configuration Configuration, fileName string) ([]byte, error) {
+
+	if documentCodec, isDocumentCodec := codec.(DocumentCodec); isDocumentCodec {
+		if document, err := ioutil.ReadFile(filepath.FromSlash(fileName)); err == nil {
+			return documentCodec.Patch(document, configuration)
+		}
+	}
+	return codec.Encode(configuration)
+}
+
+/*
+Patch the JSON document with the configuration: when Preserve is set only the text of the modified, added or removed
+properties changes, the comments and the layout of the other ones are kept. The RootPath is only written if the
+document already has it. A document which can't be read is replaced by the encoded configuration
+*/
+func (codec JSONCodec) Patch(document []byte, configuration Configuration) ([]byte, error) {
+
+	if !codec.Preserve {
+		return codec.Encode(configuration)
+	}
+
+	patcher := &jsonDocument{content: document}
+	root, err := patcher.parseValue()
+	if err == nil {
+		if _, remaining := nextJSONSignificant(document, root.end); remaining {
+			err = errors.New("unexpected content after the document")
+		}
+	}
+	if err == nil {
+		if !codec.Plain && patcher.isMarshallable(root) {
+			err = patcher.patchConfiguration(root, configuration, true)
+		} else {
+			err = patcher.patchPlainObject(root, configuration, true)
+		}
+	}
+	if err != nil {
+		return codec.Encode(configuration)
+	}
+	return patcher.apply(), nil
+}
+
+/*
+apply return the document's content modified by the edits
+*/
+func (document *jsonDocument) apply() []byte {
+
+	// Apply the edits from the end, a removal before an insertion at the same position
+	sort.SliceStable(document.edits, func(i, j int) bool {
+		if document.edits[i].start != document.edits[j].start {
+			return document.edits[i].start > document.edits[j].start
+		}
+		return document.edits[i].end > document.edits[j].end
+	})
+
+	content := append([]byte{}, document.content...)
+	for _, edit := range document.edits {
+		content = append(content[:edit.start], append([]byte(edit.text), content[edit.end:]...)...)
+	}
+	return content
+}
+
+/*
+edit add an edit of the document
+*/
+func (document *jsonDocument) edit(start int, end int, text string) {
+	document.edits = append(document.edits, jsonEdit{start: start, end: end, text: text})
+}
+
+/*
+parseValue locate the value at the position and move the position after it
+*/
+func (document *jsonDocument) parseValue() (*jsonNode, error) {
+
+	start, found := nextJSONSignificant(document.content, document.position)
+	if !found {
+		return nil, errors.New("value expected")
+	}
+	document.position = start
+
+	switch document.content[start] {
+	case '{':
+		return document.parseContainer('}')
+	case '[':
+		return document.parseContainer(']')
+	case '"', '\'':
+		end, err := copyJSONString(&bytes.Buffer{}, document.content, start)
+		if err != nil {
+			return nil, err
+		}
+		document.position = end
+		return &jsonNode{start: start, end: end}, nil
+	}
+
+	// Scalar value (number, true, false, null)
+	end := start
+	for end < len(document.content) && !isJSONSpace(document.content[end]) && !strings.ContainsRune(",:]}/", rune(document.content[end])) {
+		end++
+	}
+	if end == start {
+		return nil, errors.New("value expected")
+	}
+	document.position = end
+	return &jsonNode{start: start, end: end}, nil
+}
+
+/*
+parseContainer locate the members of the object (or the items of the array) at the position
+*/
+func (document *jsonDocument) parseContainer(closing byte) (*jsonNode, error) {
+
+	node := &jsonNode{start: document.position}
+	document.position++
+	for {
+		next, found := nextJSONSignificant(document.content, document.position)
+		if !found {
+			return nil, errors.New("unterminated object or array")
+		}
+		if document.content[next] == closing {
+			document.position = next + 1
+			node.end = document.position
+			return node, nil
+		}
+
+		member := &jsonMember{start: next, comma: -1}
+		if closing == '}' {
+			key, err := document.parseKey(next)
+			if err != nil {
+				return nil, err
+			}
+			member.key = key
+			colon, found := nextJSONSignificant(document.content, document.position)
+			if !found || document.content[colon] != ':' {
+				return nil, errors.New("':' expected after " + key)
+			}
+			document.position = colon + 1
+		}
+
+		value, err := document.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		member.value = value
+		if closing == '}' {
+			node.members = append(node.members, member)
+		}
+
+		next, found = nextJSONSignificant(document.content, document.position)
+		switch {
+		case found && document.content[next] == ',':
+			member.comma = next
+			document.position = next + 1
+		case found && document.content[next] == closing:
+			document.position = next
+		default:
+			return nil, errors.New("',' expected")
+		}
+	}
+}
+
+/*
+parseKey return the (quoted or unquoted) key at start and move the position after it
+*/
+func (document *jsonDocument) parseKey(start int) (string, error) {
+
+	if character := document.content[start]; character == '"' || character == '\'' {
+		var buffer bytes.Buffer
+		end, err := copyJSONString(&buffer, document.content, start)
+		if err != nil {
+			return "", err
+		}
+		var key string
+		if err := json.Unmarshal(buffer.Bytes(), &key); err != nil {
+			return "", err
+		}
+		document.position = end
+		return key, nil
+	}
+
+	end := start
+	for end < len(document.content) && (isJSONIdentifierStart(document.content[end]) || end > start && document.content[end] >= '0' && document.content[end] <= '9') {
+		end++
+	}
+	if end == start {
+		return "", errors.New("key expected")
+	}
+	document.position = end
+	return string(document.content[start:end]), nil
+}
+
+/*
+member return the member of the object with the key (nil if none)
+*/
+func (node *jsonNode) member(key string) *jsonMember {
+
+	for _, member := range node.members {
+		if member.key == key {
+			return member
+		}
+	}
+	return nil
+}
+
+/*
+isObject check if the node is an object
+*/
+func (document *jsonDocument) isObject(node *jsonNode) bool {
+	return document.content[node.start] == '{'
+}
+
+/*
+decode return the value of the node
+*/
+func (document *jsonDocument) decode(node *jsonNode) (interface{}, error) {
+
+	content, err := standardJSON(document.content[node.start:node.end])
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(content, &value)
+	return value, err
+}
+
+/*
+patchConfiguration add the edits needed by the marshallableConfiguration node to hold the configuration
+*/
+func (document *jsonDocument) patchConfiguration(node *jsonNode, configuration Configuration, isRoot bool) error {
+
+	if !document.isObject(node) {
+		return errors.New("object expected")
+	}
+	propertiesMember := node.member("properties")
+	if propertiesMember == nil || !document.isObject(propertiesMember.value) {
+		return errors.New("properties expected")
+	}
+
+	if nameMember := node.member("name"); nameMember != nil {
+		if err := document.patchValue(nameMember.value, configuration.Name()); err != nil {
+			return err
+		}
+	}
+
+	// Patch the existing properties, remove the ones no more in the configuration
+	properties := propertiesMember.value
+	var removed []*jsonMember
+	existing := make(map[string]bool)
+	for _, member := range properties.members {
+		existing[member.key] = true
+		property, exist := configuration.ownProperty(member.key)
+		if !exist {
+			removed = append(removed, member)
+			continue
+		}
+		if err := document.patchProperty(member.value, property); err != nil {
+			return err
+		}
+	}
+
+	// Add the new properties in their insertion order
+	var added []string
+	for _, key := range configuration.Keys() {
+		if !existing[key] && !(isRoot && key == RootPathKey) {
+			added = append(added, key)
+		}
+	}
+	return document.patchMembers(properties, removed, added, func(key string) ([]byte, error) {
+		property, _ := configuration.ownProperty(key)
+		return json.Marshal(toMarshallableProperty(property))
+	})
+}
+
+/*
+patchProperty add the edits needed by the marshallableProperty node to hold the property
+*/
+func (document *jsonDocument) patchProperty(node *jsonNode, property Property) error {
+
+	if document.isObject(node) {
+		if valueMember := node.member("value"); valueMember != nil {
+			if child, isConfiguration := property.Value().(Configuration); isConfiguration && document.isObject(valueMember.value) {
+
+				// A child Configuration is either a nested marshallableConfiguration or a raw JSON object
+				if valueMember.value.member("properties") != nil {
+					return document.patchConfiguration(valueMember.value, child, false)
+				}
+				if document.hasValue(valueMember.value, configurationObject(child)) {
+					return nil
+				}
+			}
+			if nameMember := node.member("name"); nameMember != nil {
+				if err := document.patchValue(nameMember.value, property.Name()); err != nil {
+					return err
+				}
+			}
+			return document.patchValue(valueMember.value, toMarshallableProperty(property).ValueAttr)
+		}
+	}
+	return document.patchValue(node, toMarshallableProperty(property))
+}
+
+/*
+patchValue replace the node's text if its value differs from the value
+*/
+func (document *jsonDocument) patchValue(node *jsonNode, value interface{}) error {
+
+	if document.hasValue(node, value) {
+		return nil
+	}
+
+	text, err := json.MarshalIndent(value, document.lineIndent(node.start), "  ")
+	if err != nil {
+		return err
+	}
+	document.edit(node.start, node.end, string(text))
+	return nil
+}
+
+/*
+isMarshallable check if the node has the marshallableConfiguration shape
+*/
+func (document *jsonDocument) isMarshallable(node *jsonNode) bool {
+
+	content, err := standardJSON(document.content[node.start:node.end])
+	if err != nil {
+		return false
+	}
+	value, err := decodeOrderedJSON(content)
+	if err != nil {
+		return false
+	}
+	_, isMarshallable := asMarshallable(value, true)
+	return isMarshallable
+}
+
+/*
+patchPlainObject add the edits needed by the plain JSON object node to hold the configuration
+*/
+func (document *jsonDocument) patchPlainObject(node *jsonNode, configuration Configuration, isRoot bool) error {
+
+	if !document.isObject(node) {
+		return errors.New("object expected")
+	}
+
+	// The root "name" is the Configuration's Name if there's no "name" property
+	hasName := isRoot && !configuration.HasProperty(NameKey)
+
+	// Patch the existing properties, remove the ones no more in the configuration
+	var removed []*jsonMember
+	existing := make(map[string]bool)
+	for _, member := range node.members {
+		existing[member.key] = true
+		property, exist := configuration.ownProperty(member.key)
+		switch {
+		case hasName && member.key == NameKey && configuration.Name() != "":
+			if err := document.patchValue(member.value, configuration.Name()); err != nil {
+				return err
+			}
+		case !exist:
+			removed = append(removed, member)
+		default:
+			if child, isConfiguration := property.Value().(Configuration); isConfiguration && document.isObject(member.value) {
+				if err := document.patchPlainObject(member.value, child, false); err != nil {
+					return err
+				}
+			} else if err := document.patchValue(member.value, plainJSONValue(property.Value())); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Add the new properties in their insertion order
+	var added []string
+	if hasName && !existing[NameKey] && configuration.Name() != "" {
+		added = append(added, NameKey)
+	}
+	for _, key := range configuration.Keys() {
+		if !existing[key] && !(isRoot && key == RootPathKey) {
+			added = append(added, key)
+		}
+	}
+	return document.patchMembers(node, removed, added, func(key string) ([]byte, error) {
+		if property, exist := configuration.ownProperty(key); exist {
+			return plainJSONContent(property.Value())
+		}
+		return json.Marshal(configuration.Name())
+	})
+}
+
+/*
+hasValue check if the node's value is the JSON value of the value
+*/
+func (document *jsonDocument) hasValue(node *jsonNode, value interface{}) bool {
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	var expected interface{}
+	if err := json.Unmarshal(content, &expected); err != nil {
+		return false
+	}
+	actual, err := document.decode(node)
+	return err == nil && reflect.DeepEqual(actual, expected)
+}
+
+/*
+configurationObject return the raw JSON object of a Configuration
+*/
+func configurationObject(configuration Configuration) map[string]interface{} {
+
+	object := make(map[string]interface{}, configuration.Size())
+	for key, property := range configuration.properties() {
+		if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+			object[key] = configurationObject(child)
+		} else {
+			object[key] = property.Value()
+		}
+	}
+	return object
+}
+
+/*
+patchMembers add the edits removing the removed members of the object and adding the added keys at its end,
+with the JSON content returned by memberValue. The added members are indented like the kept ones (or written on
+the same line in a single line object) and the comments following the kept members stay on their lines
+*/
+func (document *jsonDocument) patchMembers(object *jsonNode, removed []*jsonMember, added []string, memberValue func(key string) ([]byte, error)) error {
+
+	isRemoved := make(map[*jsonMember]bool)
+	for _, member := range removed {
+		isRemoved[member] = true
+	}
+
+	// The last kept member is the one the trailing removed members and the added ones are joined to
+	var lastKept *jsonMember
+	var trailingMembers []*jsonMember
+	for _, member := range object.members {
+		if !isRemoved[member] {
+			lastKept, trailingMembers = member, nil
+		} else {
+			trailingMembers = append(trailingMembers, member)
+		}
+	}
+
+	closing := object.end - 1
+	closingIndent := document.lineIndent(closing)
+	indent := closingIndent + "  "
+	for _, member := range object.members {
+		if !isRemoved[member] {
+			indent = document.lineIndent(member.start)
+			break
+		}
+	}
+	step := "  "
+	if strings.HasPrefix(indent, closingIndent) && len(indent) > len(closingIndent) {
+		step = indent[len(closingIndent):]
+	}
+	inline := bytes.IndexByte(document.content[object.start:object.end], '\n') < 0
+
+	// Members followed by a kept one are removed with their lines
+	if lastKept != nil {
+		for _, member := range object.members {
+			if member == lastKept {
+				break
+			}
+			if !isRemoved[member] {
+				continue
+			}
+			start, end := document.lineStart(member.start), document.lineEnd(member.comma+1)
+			if end == member.comma+1 {
+
+				// The next member is on the same line, it takes the place of the removed one and its separator
+				start = member.start
+				for end < len(document.content) && (document.content[end] == ' ' || document.content[end] == '\t') {
+					end++
+				}
+			}
+			document.edit(start, end, "")
+		}
+	}
+
+	// The trailing members are removed with their lines if they start them, else with the comma before them
+	ownLines := !inline && len(trailingMembers) > 0
+	for _, member := range trailingMembers {
+		ownLines = ownLines && document.startsLine(member.start)
+	}
+	comma := lastKept != nil && lastKept.comma >= 0
+	switch {
+	case lastKept == nil && len(object.members) > 0:
+		document.edit(object.start+1, closing, "")
+	case ownLines:
+		for _, member := range trailingMembers {
+			end := member.value.end
+			if member.comma >= 0 {
+				end = member.comma + 1
+			}
+			document.edit(document.lineStart(member.start), document.lineEnd(end), "")
+		}
+		if len(added) == 0 {
+			document.edit(lastKept.comma, lastKept.comma+1, "")
+			comma = false
+		}
+	case len(trailingMembers) > 0:
+		last := trailingMembers[len(trailingMembers)-1]
+		end := last.value.end
+		if last.comma >= 0 {
+			end = last.comma + 1
+		}
+		document.edit(lastKept.value.end, end, "")
+		comma = false
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+	texts := make([]string, len(added))
+	for index, key := range added {
+		content, err := memberValue(key)
+		if err != nil {
+			return err
+		}
+		var text bytes.Buffer
+		if inline {
+			err = json.Compact(&text, content)
+		} else {
+			err = json.Indent(&text, content, indent, step)
+		}
+		if err != nil {
+			return err
+		}
+		keyText, _ := json.Marshal(key)
+		texts[index] = string(keyText) + ": " + text.String()
+	}
+
+	// A trailing comma of the last member is kept after the added ones
+	suffix := ""
+	if comma && len(trailingMembers) == 0 {
+		suffix = ","
+	}
+	switch {
+	case lastKept == nil:
+		document.edit(object.start+1, object.start+1, "\n"+indent+strings.Join(texts, ",\n"+indent)+"\n"+closingIndent)
+	case inline:
+		document.edit(lastKept.value.end, lastKept.value.end, ", "+strings.Join(texts, ", "))
+	case comma:
+		document.insertLines(lastKept.comma+1, "", indent+strings.Join(texts, ",\n"+indent)+suffix)
+	case len(trailingMembers) == 0:
+		document.insertLines(lastKept.value.end, ",", indent+strings.Join(texts, ",\n"+indent))
+	default:
+		document.edit(lastKept.value.end, lastKept.value.end, ",\n"+indent+strings.Join(texts, ",\n"+indent))
+	}
+	return nil
+}
+
+/*
+insertLines insert the prefix at the position and the lines of text after the line of the position if only white
+spaces or a line comment follow the position, else just after the prefix
+*/
+func (document *jsonDocument) insertLines(position int, prefix string, text string) {
+
+	if end := document.lineEnd(position); end > position && document.content[end-1] == '\n' {
+		if prefix != "" {
+			document.edit(position, position, prefix)
+		}
+		document.edit(end, end, text+"\n")
+		return
+	}
+	document.edit(position, position, prefix+"\n"+text)
+}
+
+/*
+startsLine check if only white spaces precede the position on its line
+*/
+func (document *jsonDocument) startsLine(position int) bool {
+
+	start := bytes.LastIndexByte(document.content[:position], '\n') + 1
+	return strings.TrimSpace(string(document.content[start:position])) == ""
+}
+
+/*
+lineStart return the start of the line of the position if only white spaces precede the position on its line
+*/
+func (document *jsonDocument) lineStart(position int) int {
+
+	start := bytes.LastIndexByte(document.content[:position], '\n') + 1
+	if strings.TrimSpace(string(document.content[start:position])) != "" {
+		return position
+	}
+	return start
+}
+
+/*
+lineEnd return the position following the end of line if only white spaces or a line comment follow the position
+on its line
+*/
+func (document *jsonDocument) lineEnd(position int) int {
+
+	end := len(document.content)
+	if newLine := bytes.IndexByte(document.content[position:], '\n'); newLine >= 0 {
+		end = position + newLine + 1
+	}
+	if rest := strings.TrimSpace(string(document.content[position:end])); rest != "" && !strings.HasPrefix(rest, "//") {
+		return position
+	}
+	return end
+}
+
+/*
+lineIndent return the white spaces starting the line of the position
+*/
+func (document *jsonDocument) lineIndent(position int) string {
+
+	line := document.content[bytes.LastIndexByte(document.content[:position], '\n')+1 : position]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
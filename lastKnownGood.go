@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+Validator check a Configuration before it's applied, returning an error if it's invalid
+*/
+type Validator func(configuration Configuration) error
+
+/*
+Validate return the errors of all the validators for the configuration, or nil if it's valid
+*/
+func Validate(configuration Configuration, validators ...Validator) error {
+
+	var messages []string
+	for _, validator := range validators {
+		if err := validator(configuration); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return newError("eliteConfiguration.Validate()", errors.New(strings.Join(messages, "\n")))
+	}
+	return nil
+}
+
+/*
+LastKnownGoodName return the name of the last known good copy of fileName, next to it with the same extension
+("config.json" is copied to "config.last-known-good.json")
+*/
+func LastKnownGoodName(fileName string) string {
+
+	extension := filepath.Ext(fileName)
+	return strings.TrimSuffix(fileName, extension) + ".last-known-good" + extension
+}
+
+/*
+LoadLastKnownGood Load the last known good copy of fileName with the immutable API, decoded by the Codec of
+fileName, and check it with the validators
+*/
+func LoadLastKnownGood(fileName string, validators ...Validator) (Configuration, error) {
+
+	configuration, _, err := loadValidated(LastKnownGoodName(fileName), codecFor(fileName), validators)
+	return configuration, err
+}
+
+/*
+loadValidated load fileName with the immutable API and check it with the validators. The content of the file is
+returned to be copied if it's valid
+*/
+func loadValidated(fileName string, codec Codec, validators []Validator) (Configuration, []byte, error) {
+
+	content, err := readFile(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	configuration, err := decodeFile(content, fileName, codec, immutableState{}.New)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := Validate(configuration, validators...); err != nil {
+		return nil, nil, err
+	}
+	return configuration, content, nil
+}
+
+/*
+saveLastKnownGood write the content as the last known good copy of fileName. The copy is written aside then renamed
+so a crash can't leave it truncated
+*/
+func saveLastKnownGood(fileName string, content []byte) error {
+
+	copyName := filepath.FromSlash(LastKnownGoodName(fileName))
+	if err := ioutil.WriteFile(copyName+".tmp", content, 0600); err != nil {
+		return newError("ioutil.WriteFile("+copyName+".tmp)", err)
+	}
+	if err := os.Rename(copyName+".tmp", copyName); err != nil {
+		os.Remove(copyName + ".tmp")
+		return newError("os.Rename("+copyName+")", err)
+	}
+	return nil
+}
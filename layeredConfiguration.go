@@ -0,0 +1,473 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"errors"
+	"time"
+)
+
+/*
+layeredConfiguration is an internal Configuration stacking several Configurations (the layers).
+The last layer has the highest precedence
+*/
+type layeredConfiguration struct {
+	iLayers []Configuration
+}
+
+/*
+Layered return a Configuration stacking the layers, from the lowest to the highest precedence
+(Layered(defaults, file, environment, flags)). A Property is resolved through the layers from the highest one and
+child Configurations found in several layers are layered too. Size, Keys and the saved content reflect the merged view.
+Add, AddProperty and SetName modify the highest layer, Remove removes the Property from all the layers
+*/
+func Layered(layers ...Configuration) Configuration {
+
+	returnLayers := make([]Configuration, 0, len(layers))
+	for _, layer := range layers {
+		if layer != nil {
+			returnLayers = append(returnLayers, layer)
+		}
+	}
+	return layeredConfiguration{iLayers: returnLayers}
+}
+
+/*
+Layers return the layers of a Layered Configuration (from the lowest to the highest precedence),
+or the Configuration itself if it's not Layered
+*/
+func Layers(configuration Configuration) []Configuration {
+
+	if layered, isLayered := configuration.(layeredConfiguration); isLayered {
+		return append([]Configuration{}, layered.iLayers...)
+	}
+	return []Configuration{configuration}
+}
+
+/*
+top return the highest layer (a new immutable Configuration if there's no layer)
+*/
+func (configuration layeredConfiguration) top() Configuration {
+
+	if len(configuration.iLayers) == 0 {
+		return immutableConfiguration{}
+	}
+	return configuration.iLayers[len(configuration.iLayers)-1]
+}
+
+/*
+withTop return a copy of the configuration where the highest layer is replaced by the layer
+*/
+func (configuration layeredConfiguration) withTop(layer Configuration) Configuration {
+
+	layers := append([]Configuration{}, configuration.iLayers...)
+	if len(layers) == 0 {
+		layers = append(layers, layer)
+	} else {
+		layers[len(layers)-1] = layer
+	}
+	return layeredConfiguration{iLayers: layers}
+}
+
+/*
+Name get the configuration's Name, the one of the highest layer with a Name
+*/
+func (configuration layeredConfiguration) Name() string {
+
+	for index := len(configuration.iLayers) - 1; index >= 0; index-- {
+		if name := configuration.iLayers[index].Name(); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+/*
+SetName set the name of the highest layer of the new configuration returned
+*/
+func (configuration layeredConfiguration) SetName(requiredName string) Configuration {
+	return configuration.withTop(configuration.top().SetName(requiredName))
+}
+
+/*
+Value return the raw(untyped) Value of a specified named Property. If Property doesn't exist an error is returned
+*/
+func (configuration layeredConfiguration) Value(requiredName string) (interface{}, error) {
+
+	// Access to Property by its Name or its dotted path
+	if property, exist := lookupProperty(configuration, requiredName); !exist {
+		return nil, newError("Configuration.Value(\""+requiredName+"\")", errors.New("Key not found"))
+	} else {
+		return property.Value(), nil
+	}
+}
+
+/*
+ValueWithDefault return the raw(untyped) Value of a specified named Property or the specified defaultValue if Property doesn't exist
+*/
+func (configuration layeredConfiguration) ValueWithDefault(requiredName string, requiredDefaultValue interface{}) interface{} {
+	return configuration.Property(requiredName).WithDefault(requiredDefaultValue).Value()
+}
+
+/*
+String return the Value of a specified named Property converted to string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) String(requiredName string) string {
+	value, _ := stringValue(configuration, requiredName)
+	return value
+}
+
+/*
+StringWithDefault return the Value of a specified named Property converted to string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) StringWithDefault(requiredName string, requiredDefaultValue string) string {
+	if value, err := stringValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+StringValue return the Value of a specified named Property converted to string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration layeredConfiguration) StringValue(requiredName string) (string, error) {
+	return stringValue(configuration, requiredName)
+}
+
+/*
+Int return the Value of a specified named Property converted to int, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) Int(requiredName string) int {
+	value, _ := intValue(configuration, requiredName)
+	return value
+}
+
+/*
+IntWithDefault return the Value of a specified named Property converted to int, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) IntWithDefault(requiredName string, requiredDefaultValue int) int {
+	if value, err := intValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+IntValue return the Value of a specified named Property converted to int. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration layeredConfiguration) IntValue(requiredName string) (int, error) {
+	return intValue(configuration, requiredName)
+}
+
+/*
+Bool return the Value of a specified named Property converted to bool, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) Bool(requiredName string) bool {
+	value, _ := boolValue(configuration, requiredName)
+	return value
+}
+
+/*
+BoolWithDefault return the Value of a specified named Property converted to bool, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) BoolWithDefault(requiredName string, requiredDefaultValue bool) bool {
+	if value, err := boolValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+BoolValue return the Value of a specified named Property converted to bool. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration layeredConfiguration) BoolValue(requiredName string) (bool, error) {
+	return boolValue(configuration, requiredName)
+}
+
+/*
+Float return the Value of a specified named Property converted to float64, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) Float(requiredName string) float64 {
+	value, _ := floatValue(configuration, requiredName)
+	return value
+}
+
+/*
+FloatWithDefault return the Value of a specified named Property converted to float64, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) FloatWithDefault(requiredName string, requiredDefaultValue float64) float64 {
+	if value, err := floatValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+FloatValue return the Value of a specified named Property converted to float64. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration layeredConfiguration) FloatValue(requiredName string) (float64, error) {
+	return floatValue(configuration, requiredName)
+}
+
+/*
+Duration return the Value of a specified named Property converted to time.Duration, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) Duration(requiredName string) time.Duration {
+	value, _ := durationValue(configuration, requiredName)
+	return value
+}
+
+/*
+DurationWithDefault return the Value of a specified named Property converted to time.Duration, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) DurationWithDefault(requiredName string, requiredDefaultValue time.Duration) time.Duration {
+	if value, err := durationValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+DurationValue return the Value of a specified named Property converted to time.Duration. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration layeredConfiguration) DurationValue(requiredName string) (time.Duration, error) {
+	return durationValue(configuration, requiredName)
+}
+
+/*
+StringSlice return the Value of a specified named Property converted to []string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) StringSlice(requiredName string) []string {
+	value, _ := stringSliceValue(configuration, requiredName)
+	return value
+}
+
+/*
+StringSliceWithDefault return the Value of a specified named Property converted to []string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration layeredConfiguration) StringSliceWithDefault(requiredName string, requiredDefaultValue []string) []string {
+	if value, err := stringSliceValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+StringSliceValue return the Value of a specified named Property converted to []string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration layeredConfiguration) StringSliceValue(requiredName string) ([]string, error) {
+	return stringSliceValue(configuration, requiredName)
+}
+
+/*
+Add a Property to the highest layer of the new Configuration returned
+*/
+func (configuration layeredConfiguration) Add(requiredName string, optionalValue interface{}) Configuration {
+	return configuration.withTop(configuration.top().Add(requiredName, optionalValue))
+}
+
+/*
+Remove a property from all the layers of the new Configuration returned
+*/
+func (configuration layeredConfiguration) Remove(requiredName string) Configuration {
+
+	layers := make([]Configuration, len(configuration.iLayers))
+	for index, layer := range configuration.iLayers {
+		layers[index] = layer.Remove(requiredName)
+	}
+	return layeredConfiguration{iLayers: layers}
+}
+
+/*
+AddAll add the values (by name or dotted path) to the highest layer of the Configuration returned
+*/
+func (configuration layeredConfiguration) AddAll(values map[string]interface{}) Configuration {
+	return configuration.withTop(configuration.top().AddAll(values))
+}
+
+/*
+RemoveAll remove the properties with the names (or dotted paths) from all the layers of the new Configuration returned
+*/
+func (configuration layeredConfiguration) RemoveAll(requiredNames ...string) Configuration {
+
+	layers := make([]Configuration, len(configuration.iLayers))
+	for index, layer := range configuration.iLayers {
+		layers[index] = layer.RemoveAll(requiredNames...)
+	}
+	return layeredConfiguration{iLayers: layers}
+}
+
+/*
+RemovePrefix remove the properties whose dotted path starts with the prefix from all the layers of the new
+Configuration returned
+*/
+func (configuration layeredConfiguration) RemovePrefix(requiredPrefix string) Configuration {
+
+	layers := make([]Configuration, len(configuration.iLayers))
+	for index, layer := range configuration.iLayers {
+		layers[index] = layer.RemovePrefix(requiredPrefix)
+	}
+	return layeredConfiguration{iLayers: layers}
+}
+
+/*
+Merge add copies of all the properties of the configuration to the highest layer of the Configuration returned
+*/
+func (configuration layeredConfiguration) Merge(requiredConfiguration Configuration) Configuration {
+	return configuration.withTop(configuration.top().Merge(requiredConfiguration))
+}
+
+/*
+Size return the size of the merged configuration (Number of properties)
+*/
+func (configuration layeredConfiguration) Size() int {
+	return len(configuration.properties())
+}
+
+/*
+Property always return a Property with the requiredName. The Configuration one if exists, a new one else
+*/
+func (configuration layeredConfiguration) Property(requiredName string) Property {
+
+	// Access to Property by its Name or its dotted path
+	if property, exist := lookupProperty(configuration, requiredName); exist {
+		return property
+	}
+
+	// Return a new Property if not exist
+	var orphanFlag = true
+	return configuration.newProperty(requiredName, nil, orphanFlag)
+}
+
+/*
+HasProperty check if the named Property exist or not in one of the layers
+*/
+func (configuration layeredConfiguration) HasProperty(requiredName string) bool {
+
+	// Access to Property by its Name or its dotted path
+	_, exist := lookupProperty(configuration, requiredName)
+	return exist
+}
+
+/*
+AddProperty add a Property to the highest layer of the Configuration returned
+*/
+func (configuration layeredConfiguration) AddProperty(property Property) Configuration {
+	return configuration.withTop(configuration.top().AddProperty(property))
+}
+
+/*
+Keys return the names of all the properties of the layers, in the order of the lowest layer first
+*/
+func (configuration layeredConfiguration) Keys() []string {
+
+	var keys []string
+	known := make(map[string]bool)
+	for _, layer := range configuration.iLayers {
+		for _, key := range layer.Keys() {
+			if !known[key] {
+				known[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+/*
+Explain return the Properties with the requiredName of all the layers, from the highest to the lowest one.
+The first Property is the one which wins: the layers defining the whole name come first, and the layers hidden by
+a Property which isn't a Configuration in the dotted path aren't listed
+*/
+func (configuration layeredConfiguration) Explain(requiredName string) []Property {
+
+	properties := []Property{}
+	if _, exist := configuration.ownProperty(requiredName); exist {
+		var nested []Property
+		for index := len(configuration.iLayers) - 1; index >= 0; index-- {
+			layer := configuration.iLayers[index]
+			if _, own := layer.ownProperty(requiredName); own {
+				properties = append(properties, layer.Explain(requiredName)...)
+			} else {
+				nested = append(nested, layer.Explain(requiredName)...)
+			}
+		}
+		return append(properties, nested...)
+	}
+	if child, _, rest, found := childPath(configuration, requiredName); found {
+		return child.Explain(rest)
+	}
+	return properties
+}
+
+/*
+Sub always return the child Configuration with the requiredPath (layered if found in several layers).
+The Configuration one if exists, a new empty one else
+*/
+func (configuration layeredConfiguration) Sub(requiredPath string) Configuration {
+	return subConfiguration(configuration, requiredPath)
+}
+
+/*
+newChild instantiate and return an appropriate child Configuration
+*/
+func (configuration layeredConfiguration) newChild(requiredName string) Configuration {
+	return configuration.top().newChild(requiredName)
+}
+
+/*
+newProperty instantiate and return an appropriate Configuration's Property
+*/
+func (configuration layeredConfiguration) newProperty(requiredName string, optionalValue interface{}, orphanFlag bool) Property {
+	return configuration.top().newProperty(requiredName, optionalValue, orphanFlag)
+}
+
+/*
+ownProperty return the Property named exactly requiredName of the highest layer, the child Configurations of several
+layers are layered
+*/
+func (configuration layeredConfiguration) ownProperty(requiredName string) (Property, bool) {
+
+	var merged Property
+	for _, layer := range configuration.iLayers {
+		property, exist := layer.ownProperty(requiredName)
+		if !exist {
+			continue
+		}
+		merged = layeredProperty(layer, merged, property)
+	}
+	return merged, merged != nil
+}
+
+/*
+properties return the merged properties of the layers, the child Configurations of several layers are layered
+*/
+func (configuration layeredConfiguration) properties() map[string]Property {
+
+	merged := make(map[string]Property)
+	for _, layer := range configuration.iLayers {
+		for key, property := range layer.properties() {
+			merged[key] = layeredProperty(layer, merged[key], property)
+		}
+	}
+	return merged
+}
+
+/*
+layeredProperty return the Property of the layer over the lower one (nil if there's none). Two child Configurations
+are layered, with the Source of the layer's Property which wins
+*/
+func layeredProperty(layer Configuration, lower Property, property Property) Property {
+
+	if lower == nil {
+		return property
+	}
+	lowerChild, isLowerConfiguration := lower.Value().(Configuration)
+	upperChild, isUpperConfiguration := property.Value().(Configuration)
+	if !isLowerConfiguration || !isUpperConfiguration {
+		return property
+	}
+	var orphanFlag = false
+	return layer.newProperty(property.Name(), Layered(lowerChild, upperChild), orphanFlag).WithSource(property.Source())
+}
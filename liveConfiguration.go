@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+/*
+Live holds the current Configuration for concurrent readers: Get always return a consistent snapshot while a
+reloader Swap in a new one. Only immutable Configurations (or Layered ones) should be held, a mutable one could be
+modified while it's read. The zero Live holds an empty immutable Configuration
+*/
+type Live struct {
+	iSnapshot atomic.Value
+}
+
+/*
+liveSnapshot is the Configuration held by a Live, the atomic.Value always storing the same type
+*/
+type liveSnapshot struct {
+	iConfiguration Configuration
+}
+
+/*
+NewLive return a Live holding the configuration
+*/
+func NewLive(configuration Configuration) *Live {
+
+	live := &Live{}
+	live.iSnapshot.Store(&liveSnapshot{iConfiguration: configuration})
+	return live
+}
+
+/*
+Get return the current Configuration
+*/
+func (live *Live) Get() Configuration {
+
+	_, configuration := live.snapshot()
+	return configuration
+}
+
+/*
+Swap hold the new Configuration and return the previous one
+*/
+func (live *Live) Swap(newConfiguration Configuration) Configuration {
+
+	if previous := live.iSnapshot.Swap(&liveSnapshot{iConfiguration: newConfiguration}); previous != nil {
+		return previous.(*liveSnapshot).iConfiguration
+	}
+	return immutableConfiguration{}
+}
+
+/*
+CompareAndSwap hold the new Configuration only if the current one is still the old one (the same snapshot, not only
+equal values) and return whether it was swapped
+*/
+func (live *Live) CompareAndSwap(oldConfiguration Configuration, newConfiguration Configuration) bool {
+
+	snapshot, configuration := live.snapshot()
+	if !sameConfiguration(configuration, oldConfiguration) {
+		return false
+	}
+	return live.iSnapshot.CompareAndSwap(snapshot, &liveSnapshot{iConfiguration: newConfiguration})
+}
+
+/*
+Update hold the Configuration returned by update from the current one, update being called again if another
+goroutine swapped the Configuration meanwhile. Return the new Configuration
+*/
+func (live *Live) Update(update func(configuration Configuration) Configuration) Configuration {
+
+	for {
+		oldConfiguration := live.Get()
+		newConfiguration := update(oldConfiguration)
+		if live.CompareAndSwap(oldConfiguration, newConfiguration) {
+			return newConfiguration
+		}
+	}
+}
+
+/*
+snapshot return the stored liveSnapshot (nil for the zero Live) and its Configuration
+*/
+func (live *Live) snapshot() (interface{}, Configuration) {
+
+	snapshot := live.iSnapshot.Load()
+	if snapshot == nil {
+		return nil, immutableConfiguration{}
+	}
+	return snapshot, snapshot.(*liveSnapshot).iConfiguration
+}
+
+/*
+sameConfiguration check if both Configurations are the same snapshot: the immutable Configurations are equal values
+only when they share their properties and the mutable ones when they are the same pointer. The Layered
+Configurations, the only ones which aren't comparable, are the same when they stack the same layers
+*/
+func sameConfiguration(first Configuration, second Configuration) bool {
+
+	switch {
+	case first == nil || second == nil:
+		return first == nil && second == nil
+	case reflect.TypeOf(first) != reflect.TypeOf(second):
+		return false
+	case reflect.TypeOf(first).Comparable():
+		return first == second
+	}
+
+	firstLayers, secondLayers := Layers(first), Layers(second)
+	if len(firstLayers) != len(secondLayers) {
+		return false
+	}
+	for index, layer := range firstLayers {
+		if !sameConfiguration(layer, secondLayers[index]) {
+			return false
+		}
+	}
+	return true
+}
@@ -3,7 +3,10 @@ Copyright (c) 2016 EliteSystems. All rights reserved.
 */
 package eliteConfiguration
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 /*
 mutableConfiguration is an internal mutable Configuration struct
@@ -11,6 +14,7 @@ mutableConfiguration is an internal mutable Configuration struct
 type mutableConfiguration struct {
 	iName       string
 	iProperties map[string]Property
+	iOrder      []string
 }
 
 /*
@@ -34,8 +38,8 @@ Value return the raw(untyped) Value of a specified named Property. If Property d
 */
 func (configuration *mutableConfiguration) Value(requiredName string) (interface{}, error) {
 
-	// Access to Property by its Name
-	if property, exist := configuration.iProperties[requiredName]; !exist {
+	// Access to Property by its Name or its dotted path
+	if property, exist := lookupProperty(configuration, requiredName); !exist {
 		return nil, newError("Configuration.Value(\""+requiredName+"\")", errors.New("Key not found"))
 	} else {
 		return property.Value(), nil
@@ -49,14 +53,169 @@ func (configuration *mutableConfiguration) ValueWithDefault(requiredName string,
 	return configuration.Property(requiredName).WithDefault(requiredDefaultValue).Value()
 }
 
+/*
+String return the Value of a specified named Property converted to string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) String(requiredName string) string {
+	value, _ := stringValue(configuration, requiredName)
+	return value
+}
+
+/*
+StringWithDefault return the Value of a specified named Property converted to string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) StringWithDefault(requiredName string, requiredDefaultValue string) string {
+	if value, err := stringValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+StringValue return the Value of a specified named Property converted to string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *mutableConfiguration) StringValue(requiredName string) (string, error) {
+	return stringValue(configuration, requiredName)
+}
+
+/*
+Int return the Value of a specified named Property converted to int, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) Int(requiredName string) int {
+	value, _ := intValue(configuration, requiredName)
+	return value
+}
+
+/*
+IntWithDefault return the Value of a specified named Property converted to int, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) IntWithDefault(requiredName string, requiredDefaultValue int) int {
+	if value, err := intValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+IntValue return the Value of a specified named Property converted to int. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *mutableConfiguration) IntValue(requiredName string) (int, error) {
+	return intValue(configuration, requiredName)
+}
+
+/*
+Bool return the Value of a specified named Property converted to bool, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) Bool(requiredName string) bool {
+	value, _ := boolValue(configuration, requiredName)
+	return value
+}
+
+/*
+BoolWithDefault return the Value of a specified named Property converted to bool, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) BoolWithDefault(requiredName string, requiredDefaultValue bool) bool {
+	if value, err := boolValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+BoolValue return the Value of a specified named Property converted to bool. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *mutableConfiguration) BoolValue(requiredName string) (bool, error) {
+	return boolValue(configuration, requiredName)
+}
+
+/*
+Float return the Value of a specified named Property converted to float64, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) Float(requiredName string) float64 {
+	value, _ := floatValue(configuration, requiredName)
+	return value
+}
+
+/*
+FloatWithDefault return the Value of a specified named Property converted to float64, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) FloatWithDefault(requiredName string, requiredDefaultValue float64) float64 {
+	if value, err := floatValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+FloatValue return the Value of a specified named Property converted to float64. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *mutableConfiguration) FloatValue(requiredName string) (float64, error) {
+	return floatValue(configuration, requiredName)
+}
+
+/*
+Duration return the Value of a specified named Property converted to time.Duration, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) Duration(requiredName string) time.Duration {
+	value, _ := durationValue(configuration, requiredName)
+	return value
+}
+
+/*
+DurationWithDefault return the Value of a specified named Property converted to time.Duration, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) DurationWithDefault(requiredName string, requiredDefaultValue time.Duration) time.Duration {
+	if value, err := durationValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+DurationValue return the Value of a specified named Property converted to time.Duration. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *mutableConfiguration) DurationValue(requiredName string) (time.Duration, error) {
+	return durationValue(configuration, requiredName)
+}
+
+/*
+StringSlice return the Value of a specified named Property converted to []string, or the zero value if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) StringSlice(requiredName string) []string {
+	value, _ := stringSliceValue(configuration, requiredName)
+	return value
+}
+
+/*
+StringSliceWithDefault return the Value of a specified named Property converted to []string, or the specified defaultValue if Property doesn't exist or can't be converted
+*/
+func (configuration *mutableConfiguration) StringSliceWithDefault(requiredName string, requiredDefaultValue []string) []string {
+	if value, err := stringSliceValue(configuration, requiredName); err == nil {
+		return value
+	}
+	return requiredDefaultValue
+}
+
+/*
+StringSliceValue return the Value of a specified named Property converted to []string. If Property doesn't exist or can't be converted an error is returned
+*/
+func (configuration *mutableConfiguration) StringSliceValue(requiredName string) ([]string, error) {
+	return stringSliceValue(configuration, requiredName)
+}
+
 /*
 Add a Property to the Configuration returned
 */
 func (configuration *mutableConfiguration) Add(requiredName string, optionalValue interface{}) Configuration {
 
+	// Add the Property into its child Configuration if the name is a dotted path
+	if returnConfiguration, added := addToChild(configuration, requiredName, optionalValue); added {
+		return returnConfiguration
+	}
+
 	// Add new Property
 	var orphanFlag = false
-	return configuration.AddProperty(configuration.newProperty(requiredName, optionalValue, orphanFlag))
+	return configuration.AddProperty(configuration.newProperty(requiredName, childValue(configuration, requiredName, optionalValue), orphanFlag))
 
 }
 
@@ -65,13 +224,49 @@ Remove a property to the Configuration returned
 */
 func (configuration *mutableConfiguration) Remove(requiredName string) Configuration {
 
+	// Remove the Property from its child Configuration if the name is a dotted path
+	if returnConfiguration, removed := removeFromChild(configuration, requiredName); removed {
+		return returnConfiguration
+	}
+
 	if configuration.iProperties != nil {
 		delete(configuration.iProperties, requiredName)
+		configuration.iOrder = removedName(configuration.iOrder, requiredName)
 	}
 
 	return configuration
 }
 
+/*
+AddAll add the values (by name or dotted path) to the Configuration returned, in the order of their sorted names
+*/
+func (configuration *mutableConfiguration) AddAll(values map[string]interface{}) Configuration {
+	return addValues(configuration, values, addEachProperty)
+}
+
+/*
+RemoveAll remove the properties with the names (or dotted paths) from the Configuration returned
+*/
+func (configuration *mutableConfiguration) RemoveAll(requiredNames ...string) Configuration {
+	return removeNames(configuration, requiredNames, removeEachName)
+}
+
+/*
+RemovePrefix remove the properties whose dotted path starts with the prefix ("database." or "cache_") from the
+Configuration returned
+*/
+func (configuration *mutableConfiguration) RemovePrefix(requiredPrefix string) Configuration {
+	return removePrefix(configuration, requiredPrefix, removeEachName)
+}
+
+/*
+Merge add copies of all the properties of the configuration (keeping their Source) to the Configuration returned, a
+property replacing the whole existing one
+*/
+func (configuration *mutableConfiguration) Merge(requiredConfiguration Configuration) Configuration {
+	return addEachProperty(configuration, mergedProperties(requiredConfiguration, configuration))
+}
+
 /*
 Size return the size of the configuration (Number of properties)
 */
@@ -84,8 +279,8 @@ Property always return a Property with the requiredName. The Configuration one i
 */
 func (configuration *mutableConfiguration) Property(requiredName string) Property {
 
-	// Access to Property by its Name
-	if property, exist := configuration.iProperties[requiredName]; exist {
+	// Access to Property by its Name or its dotted path
+	if property, exist := lookupProperty(configuration, requiredName); exist {
 		return property
 	}
 
@@ -99,11 +294,9 @@ HasProperty check if the named Property exist or not in the Configuration
 */
 func (configuration *mutableConfiguration) HasProperty(requiredName string) bool {
 
-	// Access to Property by its Name
-	if _, exist := configuration.iProperties[requiredName]; exist {
-		return true
-	}
-	return false
+	// Access to Property by its Name or its dotted path
+	_, exist := lookupProperty(configuration, requiredName)
+	return exist
 }
 
 /*
@@ -116,12 +309,43 @@ func (configuration *mutableConfiguration) AddProperty(property Property) Config
 		configuration.iProperties = make(map[string]Property)
 	}
 
-	// Add new Property
+	// Add new Property, a new name is kept at the end of the order
+	if _, exist := configuration.iProperties[property.Name()]; !exist {
+		configuration.iOrder = append(configuration.iOrder, property.Name())
+	}
 	configuration.iProperties[property.Name()] = property
 
 	return configuration
 }
 
+/*
+Keys return the names of all the properties of the configuration in their insertion order
+*/
+func (configuration *mutableConfiguration) Keys() []string {
+	return append([]string(nil), configuration.iOrder...)
+}
+
+/*
+Explain return the Property with the requiredName (in a slice), or an empty slice if it doesn't exist
+*/
+func (configuration *mutableConfiguration) Explain(requiredName string) []Property {
+	return explainProperty(configuration, requiredName)
+}
+
+/*
+Sub always return the child Configuration with the requiredPath. The Configuration one if exists, a new empty one else
+*/
+func (configuration *mutableConfiguration) Sub(requiredPath string) Configuration {
+	return subConfiguration(configuration, requiredPath)
+}
+
+/*
+newChild instantiate and return an appropriate child Configuration
+*/
+func (configuration *mutableConfiguration) newChild(requiredName string) Configuration {
+	return &mutableConfiguration{iName: requiredName}
+}
+
 /*
 newProperty instantiate and return an appropriate Configuration's Property
 */
@@ -131,6 +355,15 @@ func (configuration *mutableConfiguration) newProperty(requiredName string, opti
 	return &mutableProperty{iName: requiredName, iValue: value, iOrphan: orphanFlag}
 }
 
+/*
+ownProperty return the Property named exactly requiredName
+*/
+func (configuration *mutableConfiguration) ownProperty(requiredName string) (Property, bool) {
+
+	property, exist := configuration.iProperties[requiredName]
+	return property, exist
+}
+
 /*
 properties return all the properties of the configuration
 */
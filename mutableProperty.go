@@ -10,6 +10,7 @@ type mutableProperty struct {
 	iName   string
 	iValue  interface{}
 	iOrphan bool
+	iSource Source
 }
 
 /*
@@ -35,3 +36,18 @@ func (property *mutableProperty) WithDefault(requiredDefaultValue interface{}) P
 	}
 	return property
 }
+
+/*
+Source get where the Property comes from
+*/
+func (property *mutableProperty) Source() Source {
+	return normalizedSource(property.iSource)
+}
+
+/*
+WithSource set the source of the Property
+*/
+func (property *mutableProperty) WithSource(requiredSource Source) Property {
+	property.iSource = requiredSource
+	return property
+}
@@ -3,6 +3,11 @@ Copyright (c) 2016 EliteSystems. All rights reserved.
 */
 package eliteConfiguration
 
+import (
+	"io"
+	"io/fs"
+)
+
 /*
 mutableState is the stateless API facade struct used to manipulate mutable Configurations
 */
@@ -18,7 +23,7 @@ func (state mutableState) New(requiredName string) Configuration {
 }
 
 /*
-Load fileName with valid JSON Content into a returned Configuration
+Load fileName into a returned Configuration, decoded by the Codec registered for its extension (JSON by default)
 */
 func (state mutableState) Load(fileName string) (Configuration, error) {
 
@@ -26,9 +31,92 @@ func (state mutableState) Load(fileName string) (Configuration, error) {
 }
 
 /*
-Save a Configuration to fileName in indented JSON format
+Save a Configuration to fileName, encoded by the Codec registered for its extension (indented JSON by default)
 */
 func (state mutableState) Save(configuration Configuration, fileName string) error {
 
 	return save(configuration, fileName)
 }
+
+/*
+LoadDotEnv fileName with KEY=VALUE lines (dotenv format, whatever its extension) into a returned Configuration
+*/
+func (state mutableState) LoadDotEnv(fileName string) (Configuration, error) {
+
+	return loadWith(fileName, DotEnvCodec{}, state.New)
+}
+
+/*
+SaveDotEnv a Configuration to fileName with KEY=VALUE lines (dotenv format, whatever its extension)
+*/
+func (state mutableState) SaveDotEnv(configuration Configuration, fileName string) error {
+
+	return saveWith(configuration, fileName, DotEnvCodec{})
+}
+
+/*
+LoadWith fileName into a returned Configuration, decoded by the codec whatever its extension (JSONCodec{Plain: true}
+to read a JSON file as a plain object even if it looks like the marshallableConfiguration format)
+*/
+func (state mutableState) LoadWith(fileName string, codec Codec) (Configuration, error) {
+
+	return loadWith(fileName, codec, state.New)
+}
+
+/*
+SaveWith a Configuration to fileName, encoded by the codec whatever its extension (JSONCodec{Preserve: true} to keep
+the comments and the layout of an existing file)
+*/
+func (state mutableState) SaveWith(configuration Configuration, fileName string, codec Codec) error {
+
+	return saveWith(configuration, fileName, codec)
+}
+
+/*
+LoadFrom reader with JSON Content (decoded by JSONCodec, whatever the registered Codecs) into a returned Configuration
+*/
+func (state mutableState) LoadFrom(reader io.Reader) (Configuration, error) {
+
+	return loadFrom(reader, state.New)
+}
+
+/*
+SaveTo a Configuration to writer in indented JSON format (encoded by JSONCodec, whatever the registered Codecs)
+*/
+func (state mutableState) SaveTo(configuration Configuration, writer io.Writer) error {
+
+	return saveTo(configuration, writer)
+}
+
+/*
+Parse JSON Content (decoded by JSONCodec, whatever the registered Codecs) into a returned Configuration
+*/
+func (state mutableState) Parse(content []byte) (Configuration, error) {
+
+	return parse(content, state.New)
+}
+
+/*
+Marshal a Configuration in indented JSON format (encoded by JSONCodec, whatever the registered Codecs)
+*/
+func (state mutableState) Marshal(configuration Configuration) ([]byte, error) {
+
+	return marshal(configuration)
+}
+
+/*
+LoadFS the file name of fsys (embed.FS, os.DirFS, ...) into a returned Configuration, with a RootPath relative to fsys
+*/
+func (state mutableState) LoadFS(fsys fs.FS, name string) (Configuration, error) {
+
+	return loadFS(fsys, name, state.New)
+}
+
+/*
+OverlayEnvironment return a Layered Configuration with the environment variables selected by options over the
+configuration, converted to the type of the values they replace
+*/
+func (state mutableState) OverlayEnvironment(configuration Configuration, options EnvironmentOptions) (Configuration, error) {
+
+	return overlayEnvironment(configuration, options, state.New)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+/*
+NameKey is the key of the Configuration's Name in the plain JSON format
+*/
+const (
+	NameKey = "name"
+)
+
+/*
+decodeOrderedJSON decode the strict JSON content, the objects are decoded as orderedObjects
+*/
+func decodeOrderedJSON(content []byte) (interface{}, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	value, err := decodeOrderedValue(decoder, content)
+	if err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			return value, nil
+		} else if err == nil {
+			err = errors.New("unexpected content after the document")
+		}
+	}
+	if syntaxError, isSyntaxError := err.(*json.SyntaxError); isSyntaxError {
+		err = fmt.Errorf("line %v: %v", bytes.Count(content[:syntaxError.Offset], []byte("\n"))+1, err)
+	}
+	return nil, err
+}
+
+/*
+decodeOrderedValue decode the next value of the decoder, the keys are located in the content
+*/
+func decodeOrderedValue(decoder *json.Decoder, content []byte) (interface{}, error) {
+
+	token, err := decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := newOrderedObject()
+		for decoder.More() {
+			start := int(decoder.InputOffset())
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedValue(decoder, content)
+			if err != nil {
+				return nil, err
+			}
+			object.set(key.(string), value)
+
+			// The key starts after the white spaces and the comma following the previous token
+			for start < len(content) && (isJSONSpace(content[start]) || content[start] == ',') {
+				start++
+			}
+			object.locate(key.(string), bytes.Count(content[:start], []byte("\n"))+1, start-bytes.LastIndexByte(content[:start], '\n'))
+		}
+		_, err := decoder.Token()
+		return object, err
+
+	case json.Delim('['):
+		items := []interface{}{}
+		for decoder.More() {
+			item, err := decodeOrderedValue(decoder, content)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := decoder.Token()
+		return items, err
+	}
+	return token, nil
+}
+
+/*
+fromPlainObject fill the configuration with a plain JSON object. A string "name" is the Configuration's Name, not a
+property, so a Configuration saved in the plain shape is loaded back with its Name
+*/
+func fromPlainObject(object *orderedObject, configuration Configuration) Configuration {
+
+	name, isString := object.values[NameKey].(string)
+	if !isString {
+		return fromObject(object, configuration)
+	}
+	values := newOrderedObject()
+	for _, key := range object.keys {
+		if key != NameKey {
+			values.set(key, object.values[key])
+		}
+	}
+	values.positions = object.positions
+	return fromObject(values, configuration.SetName(name))
+}
+
+/*
+encodePlainJSON return the plain JSON object of the configuration in the properties' order.
+The Name is written as "name" if the configuration has no "name" property
+*/
+func encodePlainJSON(configuration Configuration) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	if configuration.Name() != "" && !configuration.HasProperty(NameKey) {
+		name, _ := json.Marshal(configuration.Name())
+		buffer.WriteString("\"" + NameKey + "\":" + string(name))
+	}
+	if err := writePlainMembers(&buffer, configuration); err != nil {
+		return nil, err
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+/*
+writePlainMembers write the properties of the configuration as the members of a JSON object
+*/
+func writePlainMembers(buffer *bytes.Buffer, configuration Configuration) error {
+
+	for _, key := range configuration.Keys() {
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		keyContent, _ := json.Marshal(key)
+		buffer.Write(keyContent)
+		buffer.WriteByte(':')
+
+		valueContent, err := plainJSONContent(configuration.Property(key).Value())
+		if err != nil {
+			return err
+		}
+		buffer.Write(valueContent)
+	}
+	return nil
+}
+
+/*
+plainJSONContent return the JSON content of a value, child Configurations become plain JSON objects
+*/
+func plainJSONContent(value interface{}) ([]byte, error) {
+
+	child, isConfiguration := value.(Configuration)
+	if !isConfiguration {
+		return json.Marshal(value)
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	if err := writePlainMembers(&buffer, child); err != nil {
+		return nil, err
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+/*
+plainJSONValue return the value where the child Configurations are converted to raw JSON objects
+*/
+func plainJSONValue(value interface{}) interface{} {
+
+	if child, isConfiguration := value.(Configuration); isConfiguration {
+		return configurationObject(child)
+	}
+	return value
+}
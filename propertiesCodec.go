@@ -0,0 +1,285 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+/*
+PropertiesCodec reads and writes Java .properties files: the dotted keys ("database.pool.size") become nested
+child Configurations, unless a key is also the prefix of another one ("database" and "database.pool.size"
+are then both kept as flat names). Values are read as strings (the typed accessors convert them), the file is read
+as UTF-8 and the non ASCII characters are written with \uXXXX escapes. The Configuration's Name is not stored
+*/
+type PropertiesCodec struct {
+}
+
+/*
+Decode the properties content into the configuration
+*/
+func (codec PropertiesCodec) Decode(content []byte, configuration Configuration) (Configuration, error) {
+
+	// Read the key/value pairs in the file's order, the last duplicated key wins
+	pairs := newOrderedObject()
+	lines := strings.Split(strings.TrimPrefix(string(content), "\ufeff"), "\n")
+	for index := 0; index < len(lines); index++ {
+
+		number := index + 1
+		text := strings.TrimLeft(strings.TrimSuffix(lines[index], "\r"), " \t\f")
+		if text == "" || text[0] == '#' || text[0] == '!' {
+			continue
+		}
+
+		// Join the continuation lines (ending with an odd number of backslashes)
+		for endsWithContinuation(text) && index+1 < len(lines) {
+			index++
+			text = text[:len(text)-1] + strings.TrimLeft(strings.TrimSuffix(lines[index], "\r"), " \t\f")
+		}
+		if endsWithContinuation(text) {
+			text = text[:len(text)-1]
+		}
+
+		key, value := splitProperty(text)
+		unescapedKey, err := unescapeProperty(key)
+		if err != nil {
+			return nil, newPropertiesError(number, err.Error())
+		}
+		unescapedValue, err := unescapeProperty(value)
+		if err != nil {
+			return nil, newPropertiesError(number, err.Error())
+		}
+		pairs.set(unescapedKey, unescapedValue)
+		pairs.locate(unescapedKey, number, len(lines[number-1])-len(strings.TrimLeft(lines[number-1], " \t\f"))+1)
+	}
+
+	return fromObject(nestProperties(pairs), configuration), nil
+}
+
+/*
+newPropertiesError return a new configurationError located at the line number
+*/
+func newPropertiesError(number int, message string) error {
+	return newError("eliteConfiguration.PropertiesCodec.Decode()", fmt.Errorf("line %v: %v", number, message))
+}
+
+/*
+endsWithContinuation check if the line ends with an unescaped backslash
+*/
+func endsWithContinuation(text string) bool {
+
+	count := 0
+	for index := len(text) - 1; index >= 0 && text[index] == '\\'; index-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+/*
+splitProperty split the line on the first unescaped separator ('=', ':' or white space)
+*/
+func splitProperty(text string) (key string, value string) {
+
+	index := 0
+	for index < len(text) {
+		character := text[index]
+		if character == '\\' {
+			index += 2
+			continue
+		}
+		if character == '=' || character == ':' || character == ' ' || character == '\t' || character == '\f' {
+			break
+		}
+		index++
+	}
+	if index > len(text) {
+		index = len(text)
+	}
+
+	// A single '=' or ':' can follow the white spaces ending the key
+	value = strings.TrimLeft(text[index:], " \t\f")
+	if value != "" && (value[0] == '=' || value[0] == ':') {
+		value = value[1:]
+	}
+	return text[:index], strings.TrimLeft(value, " \t\f")
+}
+
+/*
+unescapeProperty replace the escape sequences (\t, \n, \uXXXX, ...) of a key or a value
+*/
+func unescapeProperty(text string) (string, error) {
+
+	if !strings.Contains(text, "\\") {
+		return text, nil
+	}
+
+	var buffer bytes.Buffer
+	var surrogates []uint16
+	flushSurrogates := func() {
+		if len(surrogates) > 0 {
+			buffer.WriteString(string(utf16.Decode(surrogates)))
+			surrogates = nil
+		}
+	}
+
+	for index := 0; index < len(text); index++ {
+		if text[index] != '\\' || index+1 >= len(text) {
+			flushSurrogates()
+			buffer.WriteByte(text[index])
+			continue
+		}
+		index++
+		if text[index] == 'u' {
+			if index+5 > len(text) {
+				return "", fmt.Errorf("malformed \\uXXXX escape in %v", text)
+			}
+			code, err := strconv.ParseUint(text[index+1:index+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uXXXX escape in %v", text)
+			}
+			surrogates = append(surrogates, uint16(code))
+			index += 4
+			continue
+		}
+		flushSurrogates()
+		switch text[index] {
+		case 't':
+			buffer.WriteByte('\t')
+		case 'n':
+			buffer.WriteByte('\n')
+		case 'r':
+			buffer.WriteByte('\r')
+		case 'f':
+			buffer.WriteByte('\f')
+		default:
+			buffer.WriteByte(text[index])
+		}
+	}
+	flushSurrogates()
+	return buffer.String(), nil
+}
+
+/*
+nestProperties return the object where the dotted keys are nested objects. A key stays flat if one of its
+prefixes is also a key, if it's the prefix of another key or if one of its names is empty
+*/
+func nestProperties(pairs *orderedObject) *orderedObject {
+
+	flat := make(map[string]bool)
+	for _, key := range pairs.keys {
+		for index := strings.Index(key, PathSeparator); index >= 0; {
+			if _, exist := pairs.values[key[:index]]; exist {
+				flat[key], flat[key[:index]] = true, true
+			}
+			next := strings.Index(key[index+len(PathSeparator):], PathSeparator)
+			if next < 0 {
+				break
+			}
+			index += len(PathSeparator) + next
+		}
+		for _, name := range strings.Split(key, PathSeparator) {
+			if name == "" {
+				flat[key] = true
+			}
+		}
+	}
+
+	root := newOrderedObject()
+	for _, key := range pairs.keys {
+		position := pairs.positions[key]
+		if flat[key] {
+			root.set(key, pairs.values[key])
+			root.locate(key, position.Line, position.Column)
+			continue
+		}
+		names := strings.Split(key, PathSeparator)
+		object := root
+		for _, name := range names[:len(names)-1] {
+			child, isObject := object.values[name].(*orderedObject)
+			if !isObject {
+				child = newOrderedObject()
+				object.set(name, child)
+			}
+			object = child
+		}
+		object.set(names[len(names)-1], pairs.values[key])
+		object.locate(names[len(names)-1], position.Line, position.Column)
+	}
+	return root
+}
+
+/*
+Encode the configuration in the properties format, child Configurations are written with dotted keys
+*/
+func (codec PropertiesCodec) Encode(configuration Configuration) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	if err := writeProperties(&buffer, configuration, ""); err != nil {
+		return nil, newError("eliteConfiguration.PropertiesCodec.Encode()", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+writeProperties write the properties of the configuration (and its children) prefixed by the prefix
+*/
+func writeProperties(buffer *bytes.Buffer, configuration Configuration, prefix string) error {
+
+	for _, key := range configuration.Keys() {
+		value := configuration.Property(key).Value()
+		if child, isConfiguration := value.(Configuration); isConfiguration {
+			if err := writeProperties(buffer, child, prefix+key+PathSeparator); err != nil {
+				return err
+			}
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		text, err := flatText(value)
+		if err != nil {
+			return fmt.Errorf("%v: %v", prefix+key, err)
+		}
+		buffer.WriteString(escapeProperty(prefix+key, true) + "=" + escapeProperty(text, false) + "\n")
+	}
+	return nil
+}
+
+/*
+escapeProperty return the text with the escape sequences needed by a key or a value
+*/
+func escapeProperty(text string, isKey bool) string {
+
+	var buffer bytes.Buffer
+	for index, character := range text {
+		switch {
+		case character == '\\':
+			buffer.WriteString("\\\\")
+		case character == '\t':
+			buffer.WriteString("\\t")
+		case character == '\n':
+			buffer.WriteString("\\n")
+		case character == '\r':
+			buffer.WriteString("\\r")
+		case character == '\f':
+			buffer.WriteString("\\f")
+		case character == ' ' && (isKey || index == 0):
+			buffer.WriteString("\\ ")
+		case isKey && strings.ContainsRune("=:#!", character), !isKey && index == 0 && strings.ContainsRune("#!", character):
+			buffer.WriteByte('\\')
+			buffer.WriteRune(character)
+		case character < 0x20 || character > 0x7e:
+			for _, code := range utf16.Encode([]rune{character}) {
+				buffer.WriteString(fmt.Sprintf("\\u%04X", code))
+			}
+		default:
+			buffer.WriteRune(character)
+		}
+	}
+	return buffer.String()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"fmt"
+)
+
+/*
+Kinds of Source
+*/
+const (
+	SourceProgram     = "program"
+	SourceFile        = "file"
+	SourceEnvironment = "environment"
+	SourceFlag        = "flag"
+)
+
+/*
+Source tells where a Property comes from: its Kind (SourceProgram for a programmatic Add, SourceFile,
+SourceEnvironment or SourceFlag), its Name (file path, environment variable or flag name) and the Line and Column
+of the Property in the file when the Codec knows them (0 else)
+*/
+type Source struct {
+	Kind   string
+	Name   string
+	Line   int
+	Column int
+}
+
+/*
+String return a readable Source: "file config.yaml:12:3", "environment APP_PORT", "flag -port", "program"
+*/
+func (source Source) String() string {
+
+	text := source.Kind
+	if text == "" {
+		text = SourceProgram
+	}
+	if source.Name != "" {
+		text += " " + source.Name
+	}
+	if source.Line > 0 {
+		text += fmt.Sprintf(":%v", source.Line)
+		if source.Column > 0 {
+			text += fmt.Sprintf(":%v", source.Column)
+		}
+	}
+	return text
+}
+
+/*
+normalizedSource return the source with the SourceProgram Kind if it has none
+*/
+func normalizedSource(source Source) Source {
+
+	if source.Kind == "" {
+		source.Kind = SourceProgram
+	}
+	return source
+}
+
+/*
+withSources return the configuration where all the properties (and the ones of its children) have the Kind and
+the Name of the source, keeping the Line and Column already known
+*/
+func withSources(configuration Configuration, source Source) Configuration {
+
+	for _, key := range configuration.Keys() {
+		property, _ := configuration.ownProperty(key)
+		propertySource := property.Source()
+		propertySource.Kind, propertySource.Name = source.Kind, source.Name
+
+		if child, isConfiguration := property.Value().(Configuration); isConfiguration {
+			var orphanFlag = false
+			property = configuration.newProperty(key, withSources(child, source), orphanFlag)
+		}
+		configuration = configuration.AddProperty(property.WithSource(propertySource))
+	}
+	return configuration
+}
+
+/*
+withPathSource return the configuration where the Property at requiredPath has the source
+*/
+func withPathSource(configuration Configuration, requiredPath string, source Source) Configuration {
+
+	if child, prefix, rest, found := childPath(configuration, requiredPath); found {
+		var orphanFlag = false
+		return configuration.AddProperty(configuration.newProperty(prefix, withPathSource(child, rest, source), orphanFlag))
+	}
+	return configuration.AddProperty(configuration.Property(requiredPath).WithSource(source))
+}
+
+/*
+explainProperty return the Property with the requiredName in a slice, or an empty slice if it doesn't exist
+*/
+func explainProperty(configuration Configuration, requiredName string) []Property {
+
+	if property, exist := lookupProperty(configuration, requiredName); exist {
+		return []Property{property}
+	}
+	return []Property{}
+}
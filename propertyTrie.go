@@ -0,0 +1,325 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"math/bits"
+	"sort"
+)
+
+/*
+propertyTrie is a persistent hash array mapped trie of Properties by Name. With and Without return a new trie
+sharing all the nodes but the ones of the modified path, so the immutable Configurations are modified in O(log n)
+without copying all their properties. Each Property keeps the order of its first insertion
+*/
+type propertyTrie struct {
+	iRoot *trieNode
+	iSize int
+	iNext int
+}
+
+/*
+trieNode holds the children of the 32 possible values of 5 bits of the hash, only the present ones being stored
+*/
+type trieNode struct {
+	iBitmap   uint32
+	iChildren []trieChild
+}
+
+/*
+trieChild is either a sub node or the leaves of a same hash (several leaves only for hash collisions)
+*/
+type trieChild struct {
+	iNode   *trieNode
+	iLeaves []trieLeaf
+}
+
+/*
+trieLeaf is a Property with its hash and its insertion order
+*/
+type trieLeaf struct {
+	iHash     uint32
+	iProperty Property
+	iOrder    int
+}
+
+/*
+trieBits is the number of bits of the hash consumed by each level
+*/
+const (
+	trieBits = 5
+)
+
+/*
+trieHash return the FNV-1a hash of the name
+*/
+func trieHash(name string) uint32 {
+
+	hash := uint32(2166136261)
+	for index := 0; index < len(name); index++ {
+		hash ^= uint32(name[index])
+		hash *= 16777619
+	}
+	return hash
+}
+
+/*
+Get return the Property with the requiredName
+*/
+func (trie propertyTrie) Get(requiredName string) (Property, bool) {
+
+	hash := trieHash(requiredName)
+	node := trie.iRoot
+	for shift := uint(0); node != nil; shift += trieBits {
+		bit := uint32(1) << ((hash >> shift) & 31)
+		if node.iBitmap&bit == 0 {
+			return nil, false
+		}
+		child := node.iChildren[bits.OnesCount32(node.iBitmap&(bit-1))]
+		if child.iNode != nil {
+			node = child.iNode
+			continue
+		}
+		for _, leaf := range child.iLeaves {
+			if leaf.iHash == hash && leaf.iProperty.Name() == requiredName {
+				return leaf.iProperty, true
+			}
+		}
+		return nil, false
+	}
+	return nil, false
+}
+
+/*
+Size return the number of Properties
+*/
+func (trie propertyTrie) Size() int {
+	return trie.iSize
+}
+
+/*
+With return a new trie with the property, replacing the one with the same Name (its order being kept)
+*/
+func (trie propertyTrie) With(property Property) propertyTrie {
+	return trie.withAll([]Property{property}, nil)
+}
+
+/*
+WithAll return a new trie with all the properties. The nodes are copied once for the whole batch
+*/
+func (trie propertyTrie) WithAll(properties []Property) propertyTrie {
+	return trie.withAll(properties, make(map[*trieNode]bool))
+}
+
+/*
+withAll return a new trie with all the properties, the owned nodes being modified in place
+*/
+func (trie propertyTrie) withAll(properties []Property, owned map[*trieNode]bool) propertyTrie {
+
+	for _, property := range properties {
+		leaf := trieLeaf{iHash: trieHash(property.Name()), iProperty: property, iOrder: trie.iNext}
+		root, added := trie.iRoot.with(leaf, 0, owned)
+		trie.iRoot = root
+		if added {
+			trie.iSize++
+			trie.iNext++
+		}
+	}
+	return trie
+}
+
+/*
+Without return a new trie without the Property with the requiredName
+*/
+func (trie propertyTrie) Without(requiredName string) propertyTrie {
+	return trie.withoutAll([]string{requiredName}, nil)
+}
+
+/*
+WithoutAll return a new trie without the Properties with the names. The nodes are copied once for the whole batch
+*/
+func (trie propertyTrie) WithoutAll(names []string) propertyTrie {
+	return trie.withoutAll(names, make(map[*trieNode]bool))
+}
+
+/*
+withoutAll return a new trie without the Properties with the names, the owned nodes being modified in place
+*/
+func (trie propertyTrie) withoutAll(names []string, owned map[*trieNode]bool) propertyTrie {
+
+	for _, name := range names {
+		root, removed := trie.iRoot.without(trieHash(name), name, 0, owned)
+		if removed {
+			trie.iRoot = root
+			trie.iSize--
+		}
+	}
+	return trie
+}
+
+/*
+Names return the Names of the Properties in their insertion order
+*/
+func (trie propertyTrie) Names() []string {
+
+	leaves := make([]trieLeaf, 0, trie.iSize)
+	trie.iRoot.each(func(leaf trieLeaf) { leaves = append(leaves, leaf) })
+	sort.Slice(leaves, func(first, second int) bool { return leaves[first].iOrder < leaves[second].iOrder })
+
+	names := make([]string, len(leaves))
+	for index, leaf := range leaves {
+		names[index] = leaf.iProperty.Name()
+	}
+	return names
+}
+
+/*
+Map return the Properties in a new map
+*/
+func (trie propertyTrie) Map() map[string]Property {
+
+	properties := make(map[string]Property, trie.iSize)
+	trie.iRoot.each(func(leaf trieLeaf) { properties[leaf.iProperty.Name()] = leaf.iProperty })
+	return properties
+}
+
+/*
+editable return the node if it was copied by the current batch (owned), a copy of it else. Without batch (nil
+owned) the node is always copied
+*/
+func (node *trieNode) editable(owned map[*trieNode]bool) *trieNode {
+
+	if node == nil {
+		node = &trieNode{}
+	} else if owned[node] {
+		return node
+	} else {
+		node = &trieNode{iBitmap: node.iBitmap, iChildren: append(make([]trieChild, 0, len(node.iChildren)+1), node.iChildren...)}
+	}
+	if owned != nil {
+		owned[node] = true
+	}
+	return node
+}
+
+/*
+with return the node (copied unless owned) with the leaf and whether it was added (false if it replaced a leaf)
+*/
+func (node *trieNode) with(leaf trieLeaf, shift uint, owned map[*trieNode]bool) (*trieNode, bool) {
+
+	bit := uint32(1) << ((leaf.iHash >> shift) & 31)
+	node = node.editable(owned)
+	index := bits.OnesCount32(node.iBitmap & (bit - 1))
+
+	// A new child is inserted at its index
+	if node.iBitmap&bit == 0 {
+		node.iChildren = append(node.iChildren, trieChild{})
+		copy(node.iChildren[index+1:], node.iChildren[index:])
+		node.iChildren[index] = trieChild{iLeaves: []trieLeaf{leaf}}
+		node.iBitmap |= bit
+		return node, true
+	}
+
+	child, added := &node.iChildren[index], true
+	switch {
+
+	case child.iNode != nil:
+		child.iNode, added = child.iNode.with(leaf, shift+trieBits, owned)
+
+	case child.iLeaves[0].iHash == leaf.iHash:
+
+		// The leaves may be shared with the previous trie, they are copied
+		leaves := append([]trieLeaf{}, child.iLeaves...)
+		for leafIndex, existing := range leaves {
+			if existing.iProperty.Name() == leaf.iProperty.Name() {
+				leaf.iOrder = existing.iOrder
+				leaves[leafIndex], added = leaf, false
+				break
+			}
+		}
+		if added {
+			leaves = append(leaves, leaf)
+		}
+		child.iLeaves = leaves
+
+	default:
+
+		// Different hashes sharing the bits of this level are split into a sub node
+		var subNode *trieNode
+		for _, existing := range child.iLeaves {
+			subNode, _ = subNode.with(existing, shift+trieBits, owned)
+		}
+		child.iLeaves = nil
+		child.iNode, added = subNode.with(leaf, shift+trieBits, owned)
+	}
+	return node, added
+}
+
+/*
+without return the node (copied unless owned) without the leaf of requiredName (nil if the node is empty) and
+whether it was removed
+*/
+func (node *trieNode) without(hash uint32, requiredName string, shift uint, owned map[*trieNode]bool) (*trieNode, bool) {
+
+	if node == nil {
+		return nil, false
+	}
+	bit := uint32(1) << ((hash >> shift) & 31)
+	if node.iBitmap&bit == 0 {
+		return node, false
+	}
+	index := bits.OnesCount32(node.iBitmap & (bit - 1))
+
+	child, removed := node.iChildren[index], false
+	if child.iNode != nil {
+		child.iNode, removed = child.iNode.without(hash, requiredName, shift+trieBits, owned)
+	} else {
+		leaves := make([]trieLeaf, 0, len(child.iLeaves))
+		for _, leaf := range child.iLeaves {
+			if leaf.iHash == hash && leaf.iProperty.Name() == requiredName {
+				removed = true
+			} else {
+				leaves = append(leaves, leaf)
+			}
+		}
+		child.iLeaves = leaves
+	}
+	if !removed {
+		return node, false
+	}
+
+	// An empty child is removed from the node
+	if child.iNode == nil && len(child.iLeaves) == 0 {
+		if node.iBitmap == bit {
+			return nil, true
+		}
+		node = node.editable(owned)
+		node.iChildren = append(node.iChildren[:index], node.iChildren[index+1:]...)
+		node.iBitmap &^= bit
+		return node, true
+	}
+
+	node = node.editable(owned)
+	node.iChildren[index] = child
+	return node, true
+}
+
+/*
+each call walk with all the leaves of the node
+*/
+func (node *trieNode) each(walk func(leaf trieLeaf)) {
+
+	if node == nil {
+		return
+	}
+	for _, child := range node.iChildren {
+		if child.iNode != nil {
+			child.iNode.each(walk)
+			continue
+		}
+		for _, leaf := range child.iLeaves {
+			walk(leaf)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/*
+standardJSON return the strict JSON equivalent of a relaxed (JSONC/JSON5 like) content:
+line (//) and block comments are removed, trailing commas are removed, unquoted keys and single quoted strings are
+double quoted. Comments and commas are replaced by spaces so the lines of the syntax errors are unchanged
+*/
+func standardJSON(content []byte) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	buffer.Grow(len(content))
+
+	var lastSignificant byte
+	for index := 0; index < len(content); {
+
+		character := content[index]
+		switch {
+
+		case character == '"' || character == '\'':
+			end, err := copyJSONString(&buffer, content, index)
+			if err != nil {
+				return nil, err
+			}
+			index, lastSignificant = end, '"'
+			continue
+
+		case character == '/' && index+1 < len(content) && (content[index+1] == '/' || content[index+1] == '*'):
+			end, err := skipJSONComment(content, index)
+			if err != nil {
+				return nil, err
+			}
+			for _, skipped := range content[index:end] {
+				if skipped == '\n' {
+					buffer.WriteByte('\n')
+				} else {
+					buffer.WriteByte(' ')
+				}
+			}
+			index = end
+			continue
+
+		case character == ',':
+			if next, found := nextJSONSignificant(content, index+1); found && (content[next] == '}' || content[next] == ']') {
+				buffer.WriteByte(' ')
+				index++
+				continue
+			}
+
+		case isJSONIdentifierStart(character) && (lastSignificant == '{' || lastSignificant == ','):
+			end := index + 1
+			for end < len(content) && (isJSONIdentifierStart(content[end]) || content[end] >= '0' && content[end] <= '9') {
+				end++
+			}
+			if next, found := nextJSONSignificant(content, end); found && content[next] == ':' {
+				buffer.WriteByte('"')
+				buffer.Write(content[index:end])
+				buffer.WriteByte('"')
+				index, lastSignificant = end, '"'
+				continue
+			}
+		}
+
+		buffer.WriteByte(character)
+		if !isJSONSpace(character) {
+			lastSignificant = character
+		}
+		index++
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+copyJSONString copy the string starting at index as a double quoted string and return the index following it
+*/
+func copyJSONString(buffer *bytes.Buffer, content []byte, index int) (int, error) {
+
+	quote := content[index]
+	buffer.WriteByte('"')
+	for position := index + 1; position < len(content); position++ {
+		switch character := content[position]; {
+		case character == '\\' && position+1 < len(content):
+			position++
+			if content[position] == '\'' {
+				buffer.WriteByte('\'')
+			} else {
+				buffer.WriteByte('\\')
+				buffer.WriteByte(content[position])
+			}
+		case character == quote:
+			buffer.WriteByte('"')
+			return position + 1, nil
+		case character == '"':
+			buffer.WriteString("\\\"")
+		case character == '\n':
+			return 0, newRelaxedJSONError(content, index, "unterminated string")
+		default:
+			buffer.WriteByte(character)
+		}
+	}
+	return 0, newRelaxedJSONError(content, index, "unterminated string")
+}
+
+/*
+skipJSONComment return the index following the comment starting at index
+*/
+func skipJSONComment(content []byte, index int) (int, error) {
+
+	if content[index+1] == '/' {
+		if end := bytes.IndexByte(content[index:], '\n'); end >= 0 {
+			return index + end, nil
+		}
+		return len(content), nil
+	}
+	if end := bytes.Index(content[index+2:], []byte("*/")); end >= 0 {
+		return index + 2 + end + 2, nil
+	}
+	return 0, newRelaxedJSONError(content, index, "unterminated comment")
+}
+
+/*
+nextJSONSignificant return the index of the next character which isn't a space or in a comment
+*/
+func nextJSONSignificant(content []byte, index int) (int, bool) {
+
+	for index < len(content) {
+		switch {
+		case isJSONSpace(content[index]):
+			index++
+		case content[index] == '/' && index+1 < len(content) && (content[index+1] == '/' || content[index+1] == '*'):
+			end, err := skipJSONComment(content, index)
+			if err != nil {
+				return 0, false
+			}
+			index = end
+		default:
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+/*
+isJSONSpace check if the character is a JSON white space
+*/
+func isJSONSpace(character byte) bool {
+	return character == ' ' || character == '\t' || character == '\n' || character == '\r'
+}
+
+/*
+isJSONIdentifierStart check if the character can start an unquoted key
+*/
+func isJSONIdentifierStart(character byte) bool {
+	return character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z' || character == '_' || character == '$'
+}
+
+/*
+newRelaxedJSONError return a new configurationError located at the line of the index
+*/
+func newRelaxedJSONError(content []byte, index int, message string) error {
+	return newError("eliteConfiguration.JSONCodec.Decode()", fmt.Errorf("line %v: %v", bytes.Count(content[:index], []byte("\n"))+1, message))
+}
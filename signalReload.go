@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+/*
+SignalOptions configure ReloadOnSignal: the Signals triggering a reload (SIGHUP by default), the Validators checking
+the reloaded Configuration and the Subscribers called with each Configuration applied and its Changes
+*/
+type SignalOptions struct {
+	Signals     []os.Signal
+	Validators  []Validator
+	Subscribers []ChangeCallback
+}
+
+/*
+ReloadOnSignal Load fileName with the api and Load it again each time the process receives one of the Signals, until
+the ctx is done. The valid Configurations are held by the returned Live and published to the Subscribers.
+The reload errors are sent to the returned channel (closed when ctx is done), the previous Configuration being kept;
+errors are dropped if the channel isn't read
+*/
+func ReloadOnSignal(ctx context.Context, api API, fileName string, options SignalOptions) (*Live, <-chan error, error) {
+
+	configuration, err := loadAndValidate(api, fileName, options.Validators)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signals := options.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	notifications := make(chan os.Signal, 1)
+	signal.Notify(notifications, signals...)
+
+	live, errorsChannel := NewLive(configuration), make(chan error, 8)
+	go func() {
+		defer close(errorsChannel)
+		defer signal.Stop(notifications)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-notifications:
+				configuration, err := loadAndValidate(api, fileName, options.Validators)
+				if err != nil {
+					select {
+					case errorsChannel <- err:
+					default:
+					}
+					continue
+				}
+				if changes := Diff(live.Swap(configuration), configuration); len(changes) > 0 {
+					for _, subscriber := range options.Subscribers {
+						subscriber(configuration, changes)
+					}
+				}
+			}
+		}
+	}()
+	return live, errorsChannel, nil
+}
+
+/*
+loadAndValidate Load fileName with the api and check it with the validators
+*/
+func loadAndValidate(api API, fileName string, validators []Validator) (Configuration, error) {
+
+	configuration, err := api.Load(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(configuration, validators...); err != nil {
+		return nil, err
+	}
+	return configuration, nil
+}
@@ -0,0 +1,515 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/*
+TagName is the struct field's tag used by Bind to read the Configuration's key, default value and required flag
+(`conf:"key,default=value,required"`)
+*/
+const (
+	TagName = "conf"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+/*
+bindingSource give access to the raw values used to fill a struct
+*/
+type bindingSource func(requiredName string) (interface{}, bool)
+
+/*
+fieldTag is the parsed content of a struct field's conf tag
+*/
+type fieldTag struct {
+	key          string
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	skip         bool
+}
+
+/*
+Bind fill the target (a non nil pointer to a struct) from the Configuration's values, following the `conf` tags
+of its fields. Nested structs, slices, maps and pointers are filled recursively. All missing required fields and
+values which can't be converted are reported together in the returned error
+*/
+func Bind(configuration Configuration, target interface{}) error {
+
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Struct {
+		return newError("eliteConfiguration.Bind()", fmt.Errorf("Target must be a non nil pointer to a struct, not %T", target))
+	}
+
+	var messages []string
+	bindStruct(configurationSource(configuration), targetValue.Elem(), "", &messages)
+	if len(messages) > 0 {
+		return newError("eliteConfiguration.Bind()", errors.New(strings.Join(messages, "\n")))
+	}
+	return nil
+}
+
+/*
+configurationSource return a bindingSource reading the Configuration's values
+*/
+func configurationSource(configuration Configuration) bindingSource {
+
+	return func(requiredName string) (interface{}, bool) {
+		value, err := configuration.Value(requiredName)
+		return value, err == nil
+	}
+}
+
+/*
+mapSource return a bindingSource reading the values of a raw JSON object
+*/
+func mapSource(values map[string]interface{}) bindingSource {
+
+	return func(requiredName string) (interface{}, bool) {
+		value, exist := values[requiredName]
+		return value, exist
+	}
+}
+
+/*
+newSource return a bindingSource for a raw value representing an object, or false if the value is not an object
+*/
+func newSource(value interface{}) (bindingSource, bool) {
+
+	switch typedValue := value.(type) {
+	case Configuration:
+		return configurationSource(typedValue), true
+	case map[string]interface{}:
+		return mapSource(typedValue), true
+	}
+	return nil, false
+}
+
+/*
+parseFieldTag read the conf tag of a struct field. The default option take all the following options
+until a "required" one, so a default value may contain commas
+*/
+func parseFieldTag(field reflect.StructField) fieldTag {
+
+	tag, hasTag := field.Tag.Lookup(TagName)
+	if tag == "-" {
+		return fieldTag{skip: true}
+	}
+
+	options := strings.Split(tag, ",")
+	returnTag := fieldTag{key: options[0]}
+	if !hasTag || returnTag.key == "" {
+		returnTag.key = field.Name
+	}
+
+	var defaultParts []string
+	inDefault := false
+	for _, option := range options[1:] {
+		switch {
+		case option == "required":
+			returnTag.required = true
+			inDefault = false
+		case strings.HasPrefix(option, "default="):
+			returnTag.hasDefault = true
+			defaultParts = []string{strings.TrimPrefix(option, "default=")}
+			inDefault = true
+		case inDefault:
+			defaultParts = append(defaultParts, option)
+		}
+	}
+	returnTag.defaultValue = strings.Join(defaultParts, ",")
+
+	return returnTag
+}
+
+/*
+joinKey return the dotted key of a field from its parent's key
+*/
+func joinKey(parentKey string, key string) string {
+
+	if parentKey == "" {
+		return key
+	}
+	return parentKey + "." + key
+}
+
+/*
+bindStruct fill all the exported fields of the struct target from the source
+*/
+func bindStruct(source bindingSource, target reflect.Value, parentKey string, messages *[]string) {
+
+	targetType := target.Type()
+	for index := 0; index < targetType.NumField(); index++ {
+
+		field := targetType.Field(index)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := parseFieldTag(field)
+		if tag.skip {
+			continue
+		}
+
+		// Untagged embedded structs share the keys of their parent
+		if _, hasTag := field.Tag.Lookup(TagName); field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct {
+			bindStruct(source, target.Field(index), parentKey, messages)
+			continue
+		}
+
+		key := joinKey(parentKey, tag.key)
+		value, exist := source(tag.key)
+		switch {
+
+		case exist:
+			bindValue(value, target.Field(index), key, messages)
+
+		case tag.hasDefault:
+			bindValue(tag.defaultValue, target.Field(index), key, messages)
+
+		case tag.required:
+			*messages = append(*messages, key+": required value not found")
+
+		case indirectType(field.Type).Kind() == reflect.Struct && indirectType(field.Type) != durationType:
+			// Missing nested structs are still visited to apply their defaults and check their required fields
+			fieldValue := target.Field(index)
+			switch {
+			case field.Type.Kind() != reflect.Ptr:
+				bindStruct(mapSource(nil), fieldValue, key, messages)
+			case !fieldValue.IsNil():
+				bindStruct(mapSource(nil), fieldValue.Elem(), key, messages)
+			default:
+				nestedValue := reflect.New(field.Type.Elem())
+				bindStruct(mapSource(nil), nestedValue.Elem(), key, messages)
+				if !nestedValue.Elem().IsZero() {
+					fieldValue.Set(nestedValue)
+				}
+			}
+		}
+	}
+}
+
+/*
+indirectType return the type pointed by pointerType, or pointerType itself if it's not a pointer
+*/
+func indirectType(pointerType reflect.Type) reflect.Type {
+
+	if pointerType.Kind() == reflect.Ptr {
+		return pointerType.Elem()
+	}
+	return pointerType
+}
+
+/*
+bindValue convert the raw value and set it into target, reporting a message if it's not possible
+*/
+func bindValue(value interface{}, target reflect.Value, key string, messages *[]string) {
+
+	mismatch := func() {
+		*messages = append(*messages, fmt.Sprintf("%v: value %v (%T) can't be converted to %v", key, value, value, target.Type()))
+	}
+
+	if target.Type() == durationType {
+		if typedValue, ok := toDuration(value); ok {
+			target.SetInt(int64(typedValue))
+		} else {
+			mismatch()
+		}
+		return
+	}
+
+	// Types like time.Time are read from their textual form
+	if target.Kind() != reflect.Ptr && reflect.PtrTo(target.Type()).Implements(textUnmarshalerType) {
+		if typedValue, ok := toString(value); !ok || target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(typedValue)) != nil {
+			mismatch()
+		}
+		return
+	}
+
+	switch target.Kind() {
+
+	case reflect.Interface:
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+		} else if reflect.TypeOf(value).Implements(target.Type()) {
+			target.Set(reflect.ValueOf(value))
+		} else {
+			mismatch()
+		}
+
+	case reflect.Ptr:
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return
+		}
+		pointedValue := reflect.New(target.Type().Elem())
+		nestedMessages := len(*messages)
+		bindValue(value, pointedValue.Elem(), key, messages)
+		if len(*messages) == nestedMessages {
+			target.Set(pointedValue)
+		}
+
+	case reflect.String:
+		if typedValue, ok := toString(value); ok {
+			target.SetString(typedValue)
+		} else {
+			mismatch()
+		}
+
+	case reflect.Bool:
+		if typedValue, ok := toBool(value); ok {
+			target.SetBool(typedValue)
+		} else {
+			mismatch()
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typedValue, ok := toInt(value); ok && !target.OverflowInt(int64(typedValue)) {
+			target.SetInt(int64(typedValue))
+		} else {
+			mismatch()
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if typedValue, ok := toInt(value); ok && typedValue >= 0 && !target.OverflowUint(uint64(typedValue)) {
+			target.SetUint(uint64(typedValue))
+		} else {
+			mismatch()
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if typedValue, ok := toFloat(value); ok && !target.OverflowFloat(typedValue) {
+			target.SetFloat(typedValue)
+		} else {
+			mismatch()
+		}
+
+	case reflect.Slice:
+		bindSlice(value, target, key, messages, mismatch)
+
+	case reflect.Map:
+		bindMap(value, target, key, messages, mismatch)
+
+	case reflect.Struct:
+		if source, ok := newSource(value); ok {
+			bindStruct(source, target, key, messages)
+		} else {
+			mismatch()
+		}
+
+	default:
+		mismatch()
+	}
+}
+
+/*
+bindSlice fill the slice target from a raw array (or a comma separated string for slices of strings)
+*/
+func bindSlice(value interface{}, target reflect.Value, key string, messages *[]string, mismatch func()) {
+
+	var items []interface{}
+	switch typedValue := value.(type) {
+	case []interface{}:
+		items = typedValue
+	case nil:
+		target.Set(reflect.Zero(target.Type()))
+		return
+	default:
+		stringItems, ok := toStringSlice(value)
+		if !ok {
+			mismatch()
+			return
+		}
+		for _, item := range stringItems {
+			items = append(items, item)
+		}
+	}
+
+	sliceValue := reflect.MakeSlice(target.Type(), len(items), len(items))
+	for index, item := range items {
+		bindValue(item, sliceValue.Index(index), fmt.Sprintf("%v[%v]", key, index), messages)
+	}
+	target.Set(sliceValue)
+}
+
+/*
+bindMap fill the map target (with string keys) from a raw object or a child Configuration
+*/
+func bindMap(value interface{}, target reflect.Value, key string, messages *[]string, mismatch func()) {
+
+	items, ok := value.(map[string]interface{})
+	if configuration, isConfiguration := value.(Configuration); isConfiguration {
+		items, ok = make(map[string]interface{}, configuration.Size()), true
+		for key, property := range configuration.properties() {
+			items[key] = property.Value()
+		}
+	}
+	if !ok || target.Type().Key().Kind() != reflect.String {
+		mismatch()
+		return
+	}
+
+	mapValue := reflect.MakeMapWithSize(target.Type(), len(items))
+	for itemKey, item := range items {
+		itemValue := reflect.New(target.Type().Elem()).Elem()
+		bindValue(item, itemValue, joinKey(key, itemKey), messages)
+		mapValue.SetMapIndex(reflect.ValueOf(itemKey).Convert(target.Type().Key()), itemValue)
+	}
+	target.Set(mapValue)
+}
+
+/*
+FromStruct return a new Configuration created by the api facade from the source (a struct or a pointer to a struct),
+following the `conf` tags of its fields. Zero fields with a default value take it, nested structs become objects
+*/
+func FromStruct(api API, source interface{}) (Configuration, error) {
+
+	sourceValue := reflect.Indirect(reflect.ValueOf(source))
+	if sourceValue.Kind() != reflect.Struct {
+		return nil, newError("eliteConfiguration.FromStruct()", fmt.Errorf("Source must be a struct or a pointer to a struct, not %T", source))
+	}
+
+	var messages []string
+	var configuration = api.New(sourceValue.Type().Name())
+	for _, item := range structValues(sourceValue, "", &messages) {
+		configuration = configuration.Add(item.key, item.value)
+	}
+
+	if len(messages) > 0 {
+		return nil, newError("eliteConfiguration.FromStruct()", errors.New(strings.Join(messages, "\n")))
+	}
+	return configuration, nil
+}
+
+/*
+structValue is a key/value pair read from a struct field
+*/
+type structValue struct {
+	key   string
+	value interface{}
+}
+
+/*
+structValues return the raw values of all the exported fields of the struct source, in their declaration order
+*/
+func structValues(source reflect.Value, parentKey string, messages *[]string) []structValue {
+
+	var values []structValue
+	sourceType := source.Type()
+	for index := 0; index < sourceType.NumField(); index++ {
+
+		field := sourceType.Field(index)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := parseFieldTag(field)
+		if tag.skip {
+			continue
+		}
+
+		// Untagged embedded structs share the keys of their parent
+		if _, hasTag := field.Tag.Lookup(TagName); field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct {
+			values = append(values, structValues(source.Field(index), parentKey, messages)...)
+			continue
+		}
+
+		fieldValue := source.Field(index)
+		key := joinKey(parentKey, tag.key)
+		if fieldValue.IsZero() && tag.hasDefault {
+			defaultValue := reflect.New(field.Type).Elem()
+			bindValue(tag.defaultValue, defaultValue, key, messages)
+			fieldValue = defaultValue
+		}
+
+		if value, ok := rawValue(fieldValue, key, messages); ok {
+			values = append(values, structValue{key: tag.key, value: value})
+		}
+	}
+	return values
+}
+
+/*
+rawValue convert a Go value to a raw Configuration's value. Nil pointers and interfaces are ignored
+*/
+func rawValue(value reflect.Value, key string, messages *[]string) (interface{}, bool) {
+
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String(), true
+	}
+
+	// Types like time.Time are written in their textual form
+	if value.Kind() != reflect.Ptr && value.Kind() != reflect.Interface && value.Type().Implements(textMarshalerType) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			*messages = append(*messages, fmt.Sprintf("%v: %v", key, err))
+			return nil, false
+		}
+		return string(text), true
+	}
+
+	switch value.Kind() {
+
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil, false
+		}
+		return rawValue(value.Elem(), key, messages)
+
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Interface(), true
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil, false
+		}
+		items := make([]interface{}, 0, value.Len())
+		for index := 0; index < value.Len(); index++ {
+			if item, ok := rawValue(value.Index(index), fmt.Sprintf("%v[%v]", key, index), messages); ok {
+				items = append(items, item)
+			}
+		}
+		return items, true
+
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			*messages = append(*messages, fmt.Sprintf("%v: map keys must be strings, not %v", key, value.Type().Key()))
+			return nil, false
+		}
+		if value.IsNil() {
+			return nil, false
+		}
+		items := make(map[string]interface{}, value.Len())
+		for _, mapKey := range value.MapKeys() {
+			if item, ok := rawValue(value.MapIndex(mapKey), joinKey(key, mapKey.String()), messages); ok {
+				items[mapKey.String()] = item
+			}
+		}
+		return items, true
+
+	case reflect.Struct:
+		items := make(map[string]interface{})
+		for _, item := range structValues(value, key, messages) {
+			items[item.key] = item.value
+		}
+		return items, true
+	}
+
+	*messages = append(*messages, fmt.Sprintf("%v: type %v is not supported", key, value.Type()))
+	return nil, false
+}
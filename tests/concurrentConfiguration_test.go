@@ -0,0 +1,155 @@
+package eliteConfiguration_test
+
+import (
+	"fmt"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+/*
+Try to modify a concurrent Configuration in place
+*/
+func TestConcurrentConfigurationInPlace(t *testing.T) {
+
+	configuration := conf.Concurrent().New("concurrent")
+	configuration.Add("database.host", "localhost").Add("Key1", "Value1")
+	database := configuration.Sub("database")
+	database.Add("port", 5432)
+
+	switch {
+
+	case configuration.Size() != 2 || configuration.String("Key1") != "Value1":
+		t.Error("Add() should modify the Configuration in place")
+
+	case configuration.Int("database.port") != 5432:
+		t.Error("child Configurations should be modified in place")
+
+	case configuration.Property("Key1").WithSource(conf.Source{Kind: conf.SourceFlag}) == nil || configuration.Property("Key1").Source().Kind != conf.SourceProgram:
+		t.Error("returned Properties should not modify the Configuration")
+	}
+}
+
+/*
+Try to read and modify a concurrent Configuration from several goroutines (run with -race)
+*/
+func TestConcurrentConfigurationRace(t *testing.T) {
+
+	configuration := conf.Concurrent().New("concurrent").Add("database.pool.size", 10)
+
+	var waitGroup sync.WaitGroup
+	for goroutine := 0; goroutine < 8; goroutine++ {
+		waitGroup.Add(1)
+		go func(goroutine int) {
+			defer waitGroup.Done()
+			for index := 0; index < 100; index++ {
+				key := fmt.Sprintf("key%v_%v", goroutine, index)
+				configuration.Add(key, index)
+				configuration.Add("database.pool.size", index)
+				configuration.Sub("database").Add("host", key)
+
+				if value, err := configuration.Value(key); err != nil || value != index {
+					t.Errorf("%v should be readable after Add()", key)
+				}
+				configuration.Int("database.pool.size")
+				configuration.Property("database").Value().(conf.Configuration).Keys()
+				configuration.Keys()
+				configuration.Explain("database.host")
+				if index%2 == 0 {
+					configuration.Remove(key)
+				}
+			}
+		}(goroutine)
+	}
+
+	// Readers run with the writers, the Configuration is also encoded meanwhile
+	for reader := 0; reader < 4; reader++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 50; index++ {
+				if _, err := conf.Concurrent().Marshal(configuration); err != nil {
+					t.Errorf("Marshal() should not return an error: %v", err)
+				}
+				configuration.Size()
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if configuration.Size() != 8*50+1 || configuration.Sub("database").Size() != 2 {
+		t.Errorf("all the modifications should be applied, size is %v", configuration.Size())
+	}
+}
+
+/*
+Try to modify the slices and maps returned by a concurrent Configuration, the Configuration should be unchanged
+*/
+func TestConcurrentConfigurationCopies(t *testing.T) {
+
+	configuration := conf.Concurrent().New("concurrent").Add("hosts", []string{"a", "b"}).Add("items", []interface{}{"x", map[string]interface{}{"k": 1}}).Add("labels", map[string]string{"env": "dev"})
+
+	configuration.StringSlice("hosts")[0] = "modified"
+	configuration.Keys()[0] = "modified"
+	hosts, _ := configuration.Value("hosts")
+	hosts.([]string)[1] = "modified"
+	items := configuration.Property("items").Value().([]interface{})
+	items[0] = "modified"
+	items[1].(map[string]interface{})["k"] = 2
+	configuration.ValueWithDefault("labels", nil).(map[string]string)["env"] = "modified"
+
+	switch {
+
+	case !reflect.DeepEqual(configuration.StringSlice("hosts"), []string{"a", "b"}):
+		t.Errorf("hosts should be unchanged not %v", configuration.StringSlice("hosts"))
+
+	case !reflect.DeepEqual(configuration.Keys(), []string{"hosts", "items", "labels"}):
+		t.Errorf("Keys should be unchanged not %v", configuration.Keys())
+
+	case !reflect.DeepEqual(configuration.Property("items").Value(), []interface{}{"x", map[string]interface{}{"k": 1}}):
+		t.Errorf("items should be unchanged not %v", configuration.Property("items").Value())
+
+	case !reflect.DeepEqual(configuration.Property("labels").Value(), map[string]string{"env": "dev"}):
+		t.Errorf("labels should be unchanged not %v", configuration.Property("labels").Value())
+	}
+}
+
+/*
+Try to add each of two concurrent Configurations into the other from several goroutines (run with -race), the
+added children are copies so no deadlock can happen
+*/
+func TestConcurrentConfigurationForeignChild(t *testing.T) {
+
+	first := conf.Concurrent().New("first").Add("database.host", "first")
+	second := conf.Concurrent().New("second").Add("cache.host", "second")
+
+	var waitGroup sync.WaitGroup
+	for goroutine := 0; goroutine < 4; goroutine++ {
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 100; index++ {
+				first.Add("other", second.Sub("cache"))
+			}
+		}()
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 100; index++ {
+				second.AddProperty(first.Property("database")).Merge(conf.Concurrent().New("").Add("copy", first.Sub("database")))
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	first.Add("database.host", "modified")
+	second.Add("cache.host", "modified")
+	switch {
+
+	case first.String("other.host") != "second":
+		t.Errorf("other.host should be second not %v", first.String("other.host"))
+
+	case second.String("database.host") != "first" || second.String("copy.host") != "first":
+		t.Error("the added children should be copies, unchanged by their original Configuration")
+	}
+}
@@ -0,0 +1,153 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"reflect"
+	"testing"
+)
+
+/*
+Try to AddAll values to a Configuration
+*/
+func TestAddAllConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("batch").Add("Key1", "Value1").Add("database.host", "localhost")
+		configuration = configuration.AddAll(map[string]interface{}{
+			"Key3": "Value3", "Key2": "Value2", "Key1": "Value0",
+			"database.port": 5432, "cache": map[string]interface{}{"size": 10},
+		})
+
+		switch {
+
+		case configuration.Size() != 5 || !reflect.DeepEqual(configuration.Keys(), []string{"Key1", "database", "Key2", "Key3", "cache"}):
+			t.Errorf("%v: new names should be added in their sorted order not %v", apiName, configuration.Keys())
+
+		case configuration.String("Key1") != "Value0" || configuration.String("Key2") != "Value2":
+			t.Errorf("%v: values should be added and replaced", apiName)
+
+		case configuration.Int("database.port") != 5432 || configuration.String("database.host") != "localhost" || configuration.Int("cache.size") != 10:
+			t.Errorf("%v: dotted paths and objects should be added as children", apiName)
+		}
+	}
+}
+
+/*
+Try to AddAll several dotted paths of the same children
+*/
+func TestAddAllChildren(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		file, err := api.Load(plainConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		configuration := file.AddAll(map[string]interface{}{
+			"database.port": 5432, "database.pool.size": 20, "database.pool.idle": 2, "server.port": 80, "server.host": "web",
+			"object": map[string]interface{}{"key": 1}, "object.other": 2,
+		})
+
+		switch {
+
+		case !reflect.DeepEqual(configuration.Keys(), []string{"Key1", "database", "hosts", conf.RootPathKey, "object", "server"}):
+			t.Errorf("%v: each child should be added once in the sorted order not %v", apiName, configuration.Keys())
+
+		case configuration.Int("database.port") != 5432 || configuration.Int("database.pool.size") != 20 || configuration.Int("database.pool.idle") != 2 || configuration.String("database.host") != "localhost":
+			t.Errorf("%v: the existing children should get all their values", apiName)
+
+		case configuration.Int("server.port") != 80 || configuration.String("server.host") != "web":
+			t.Errorf("%v: the new children should get all their values", apiName)
+
+		case configuration.Int("object.key") != 1 || configuration.Int("object.other") != 2:
+			t.Errorf("%v: a dotted path into an added object should be added like Add() does", apiName)
+
+		case configuration.Property("database").Source() != file.Property("database").Source():
+			t.Errorf("%v: the existing children should keep their Source not %v", apiName, configuration.Property("database").Source())
+		}
+	}
+}
+
+/*
+Try to RemoveAll and RemovePrefix properties of a Configuration
+*/
+func TestRemoveAllConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		newConfiguration := func() conf.Configuration {
+			return api.New("batch").AddAll(map[string]interface{}{"Key1": 1, "Key2": 2, "Key3": 3, "cache_size": 10, "cache_ttl": "1m"}).
+				Add("database.host", "localhost").Add("database.pool.size", 10).Add("database.pool.idle", 2)
+		}
+
+		configuration := newConfiguration().RemoveAll("Key1", "Key3", "database.host", "missing")
+		switch {
+
+		case configuration.Size() != 4 || configuration.HasProperty("Key1") || configuration.HasProperty("Key3") || !configuration.HasProperty("Key2"):
+			t.Errorf("%v: RemoveAll() should remove the names not %v", apiName, configuration.Keys())
+
+		case configuration.HasProperty("database.host") || configuration.Int("database.pool.size") != 10:
+			t.Errorf("%v: RemoveAll() should remove the dotted paths", apiName)
+		}
+
+		configuration = newConfiguration().RemovePrefix("cache_").RemovePrefix("database.pool.")
+		switch {
+
+		case configuration.Size() != 4 || configuration.HasProperty("cache_size") || configuration.HasProperty("cache_ttl"):
+			t.Errorf("%v: RemovePrefix() should remove the prefixed names not %v", apiName, configuration.Keys())
+
+		case configuration.HasProperty("database.pool") || configuration.String("database.host") != "localhost":
+			t.Errorf("%v: RemovePrefix() should remove the prefixed paths of the children and the emptied children", apiName)
+
+		case newConfiguration().RemovePrefix("database.").HasProperty("database") || !api.New("").Add("empty", api.New("empty")).RemovePrefix("empty.").HasProperty("empty"):
+			t.Errorf("%v: RemovePrefix() should only remove the children it empties", apiName)
+
+		case newConfiguration().RemovePrefix("").Size() != 0:
+			t.Errorf("%v: an empty prefix should remove all the properties", apiName)
+		}
+	}
+}
+
+/*
+Try to Merge Configurations
+*/
+func TestMergeConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		base := api.New("base").Add("Key1", "Value1").Add("database.host", "localhost").Add("database.port", 5432)
+		overlay := conf.Immutable().New("overlay").Add("Key2", "Value2").Add("database.host", "db.example.com").
+			AddProperty(conf.Immutable().New("").Property("Key1").WithDefault("Value0").WithSource(conf.Source{Kind: conf.SourceFlag, Name: "-Key1"}))
+		configuration := base.Merge(overlay)
+
+		switch {
+
+		case !reflect.DeepEqual(configuration.Keys(), []string{"Key1", "database", "Key2"}) || configuration.Name() != "base":
+			t.Errorf("%v: Merge() should add the new properties after the existing ones not %v", apiName, configuration.Keys())
+
+		case configuration.String("Key1") != "Value0" || configuration.Property("Key1").Source().Kind != conf.SourceFlag:
+			t.Errorf("%v: merged properties should replace the existing ones with their Source", apiName)
+
+		case configuration.String("database.host") != "db.example.com" || configuration.HasProperty("database.port"):
+			t.Errorf("%v: Merge() should replace whole child Configurations", apiName)
+		}
+
+		mutableOverlay := conf.Mutable().New("overlay").Add("database.host", "db.example.com")
+		configuration = api.New("base").Merge(mutableOverlay)
+		mutableOverlay.Add("database.port", 5433)
+		configuration.Add("database.pool.size", 10)
+		if configuration.HasProperty("database.port") || mutableOverlay.HasProperty("database.pool.size") {
+			t.Errorf("%v: Merge() should copy the child Configurations", apiName)
+		}
+	}
+
+	for apiName, api := range apis {
+
+		configuration := conf.Layered(api.New("defaults").Add("database.host", "localhost").Add("debug", false), api.New("file").Add("Key1", "Value1"),
+			api.New("").Add("debug", true)).RemoveAll("debug").AddAll(map[string]interface{}{"Key2": "Value2"})
+		if configuration.HasProperty("debug") || configuration.String("Key2") != "Value2" || len(conf.Layers(configuration)) != 3 {
+			t.Errorf("%v: batch operations should apply to the layers", apiName)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+Try to Diff two Configurations by property
+*/
+func TestDiffConfigurations(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		oldConfiguration := api.New("").Add("database.host", "localhost").Add("database.port", 5432).Add("debug", false)
+		newConfiguration := api.New("").Add("database.host", "db.example.com").Add("database.port", 5432).Add("timeout", "1s")
+		changes := conf.Diff(oldConfiguration, newConfiguration)
+
+		switch {
+
+		case len(changes) != 3:
+			t.Errorf("%v: only the changed properties should be in the ChangeSet not %v", apiName, changes)
+
+		case changes["database.host"].Kind != conf.ChangeModified || changes["database.host"].Old.Value() != "localhost" || changes["database.host"].New.Value() != "db.example.com":
+			t.Errorf("%v: nested properties should be modified", apiName)
+
+		case changes["debug"].Kind != conf.ChangeRemoved || changes["debug"].New != nil:
+			t.Errorf("%v: missing properties should be removed", apiName)
+
+		case changes["timeout"].Kind != conf.ChangeAdded || changes["timeout"].Old != nil:
+			t.Errorf("%v: new properties should be added", apiName)
+		}
+	}
+}
+
+/*
+Try to Watch a file with the system notifications and with polling
+*/
+func TestWatchConfiguration(t *testing.T) {
+
+	for optionsName, options := range map[string]conf.WatchOptions{
+		"system":  {Debounce: 20 * time.Millisecond},
+		"polling": {Debounce: 20 * time.Millisecond, Poll: true, PollInterval: 20 * time.Millisecond},
+	} {
+
+		fileName := testsPath + "watched." + optionsName + ".json"
+		if err := ioutil.WriteFile(fileName, []byte(`{"host": "localhost", "port": 5432}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		watcher, err := conf.Watch(fileName, options)
+		if err != nil {
+			t.Fatalf("%v: %v", optionsName, err)
+		}
+		reloads := make(chan conf.ChangeSet, 10)
+		watcher.OnChange(func(configuration conf.Configuration, changes conf.ChangeSet) {
+			reloads <- changes
+		})
+
+		// Several writes should only be reloaded once
+		time.Sleep(50 * time.Millisecond)
+		ioutil.WriteFile(fileName, []byte(`{"host": "db", "port": 5432}`), 0600)
+		ioutil.WriteFile(fileName, []byte(`{"host": "db.example.com", "port": 5432}`), 0600)
+
+		select {
+		case changes := <-reloads:
+			switch {
+
+			case len(changes) != 1 || changes["host"].Kind != conf.ChangeModified || changes["host"].New.Value() != "db.example.com":
+				t.Errorf("%v: the change set should only contain the host not %v", optionsName, changes)
+
+			case watcher.Configuration().String("host") != "db.example.com" || watcher.Configuration().Int("port") != 5432:
+				t.Errorf("%v: the Watcher should hold the reloaded Configuration", optionsName)
+
+			case watcher.Live().Get().String("host") != "db.example.com":
+				t.Errorf("%v: the Live of the Watcher should hold the reloaded Configuration", optionsName)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%v: the written file should be reloaded", optionsName)
+		}
+
+		select {
+		case changes := <-reloads:
+			t.Errorf("%v: successive writes should be debounced, got %v", optionsName, changes)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if err := watcher.Close(); err != nil {
+			t.Errorf("%v: Close() should not return an error: %v", optionsName, err)
+		}
+		if err := watcher.Close(); err == nil || !strings.Contains(err.Error(), "eliteConfiguration.Watcher.Close()") {
+			t.Errorf("%v: Close() should return an error once closed not %v", optionsName, err)
+		}
+		os.Remove(fileName)
+	}
+}
+
+/*
+Try to Reload an invalid file, the last Configuration should be kept
+*/
+func TestWatchInvalidConfiguration(t *testing.T) {
+
+	fileName := testsPath + "watched.invalid.json"
+	ioutil.WriteFile(fileName, []byte(`{"host": "localhost"}`), 0600)
+	defer os.Remove(fileName)
+
+	if _, err := conf.Watch(nonExistingConfigurationFile, conf.WatchOptions{}); err == nil {
+		t.Error("Watch() should return an error for a missing file")
+	}
+
+	watcher, err := conf.Watch(fileName, conf.WatchOptions{Poll: true, PollInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	var reloadError error
+	watcher.OnError(func(err error) { reloadError = err })
+	ioutil.WriteFile(fileName, []byte(`{"host": `), 0600)
+
+	if err := watcher.Reload(); err == nil || reloadError != err {
+		t.Error("Reload() should return and report the error of an invalid file")
+	}
+	if watcher.Configuration().String("host") != "localhost" {
+		t.Error("the last valid Configuration should be kept")
+	}
+}
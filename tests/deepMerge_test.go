@@ -0,0 +1,110 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"reflect"
+	"testing"
+)
+
+/*
+Try to deep Merge Configurations with the default strategies
+*/
+func TestDeepMergeConfigurations(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		base := api.New("base").Add("database.host", "localhost").Add("database.port", 5432).
+			Add("servers", []interface{}{"a", "b"}).Add("tags", []interface{}{"x"}).Add("debug", false)
+		overlay := api.New("production").Add("database.host", "db.example.com").Add("database.pool.size", 20).
+			Add("servers", []interface{}{"b", "c"}).Add("tags", []interface{}{"x"}).Add("debug", true).Add("region", "eu")
+		merged, err := conf.Merge(base, overlay, conf.MergeOptions{})
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: Merge() should not return an error: %v", apiName, err)
+
+		case merged.Name() != "base" || !reflect.DeepEqual(merged.Keys(), []string{"database", "servers", "tags", "debug", "region"}):
+			t.Errorf("%v: the base order should be kept and the new keys appended not %v", apiName, merged.Keys())
+
+		case merged.String("database.host") != "db.example.com" || merged.Int("database.port") != 5432 || merged.Int("database.pool.size") != 20:
+			t.Errorf("%v: child Configurations should be merged property by property", apiName)
+
+		case !reflect.DeepEqual(merged.StringSlice("servers"), []string{"b", "c"}) || !merged.Bool("debug") || merged.String("region") != "eu":
+			t.Errorf("%v: arrays and scalars of the overlay should replace the base ones", apiName)
+
+		case base.HasProperty("database.pool.size") || base.String("database.host") != "localhost" || overlay.HasProperty("database.port"):
+			t.Errorf("%v: the merged Configurations should not be modified", apiName)
+		}
+
+		// The merged Configuration shares nothing with the base and the overlay
+		merged.Sub("database").Add("user", "admin")
+		if merged.Sub("database.pool").Add("idle", 2); base.HasProperty("database.user") || overlay.HasProperty("database.pool.idle") {
+			t.Errorf("%v: the merged children should be copies", apiName)
+		}
+	}
+}
+
+/*
+Try to deep Merge arrays with global and per key strategies
+*/
+func TestDeepMergeArrays(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		base := api.New("base").Add("servers", []interface{}{"a", "b", "a"}).Add("tags", []interface{}{"x"}).Add("ports", []interface{}{80, 443})
+		overlay := api.New("production").Add("servers", []interface{}{"b", "c"}).Add("tags", []interface{}{"x"}).Add("ports", []interface{}{80.0, 8080.0})
+		merged, err := conf.Merge(base, overlay, conf.MergeOptions{Arrays: conf.ArrayAppend,
+			Keys: map[string]conf.MergeStrategy{"servers": {Arrays: conf.ArrayUnion}, "ports": {Arrays: conf.ArrayUnion}}})
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: Merge() should not return an error: %v", apiName, err)
+
+		case !reflect.DeepEqual(merged.StringSlice("servers"), []string{"a", "b", "c"}):
+			t.Errorf("%v: the per key unique-union should win and remove the duplicated base items not %v", apiName, merged.StringSlice("servers"))
+
+		case !reflect.DeepEqual(merged.Property("ports").Value(), []interface{}{80, 443, 8080.0}):
+			t.Errorf("%v: the numbers should be compared by value not %v", apiName, merged.Property("ports").Value())
+
+		case !reflect.DeepEqual(merged.StringSlice("tags"), []string{"x", "x"}):
+			t.Errorf("%v: the global append strategy should be used not %v", apiName, merged.StringSlice("tags"))
+		}
+
+		if _, err := conf.Merge(base, overlay, conf.MergeOptions{Arrays: "concat"}); err == nil {
+			t.Errorf("%v: unknown strategies should return an error", apiName)
+		}
+	}
+}
+
+/*
+Try to deep Merge values of conflicting types
+*/
+func TestDeepMergeConflicts(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		base := api.New("base").Add("database.host", "localhost").Add("port", 5432).Add("name", nil)
+		overlay := api.New("overlay").Add("database", "postgres://db").Add("port", "5433").Add("name", "app")
+
+		merged, err := conf.Merge(base, overlay, conf.MergeOptions{})
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: conflicts should be won by the overlay by default: %v", apiName, err)
+
+		case merged.String("database") != "postgres://db" || merged.String("port") != "5433":
+			t.Errorf("%v: the overlay values should replace the conflicting ones", apiName)
+		}
+
+		if _, err := conf.Merge(base, overlay, conf.MergeOptions{Conflicts: conf.ConflictError}); err == nil {
+			t.Errorf("%v: conflicts should return an error with the error strategy", apiName)
+		}
+
+		options := conf.MergeOptions{Conflicts: conf.ConflictError, Keys: map[string]conf.MergeStrategy{"database": {Conflicts: conf.ConflictOverlay}, "port": {Conflicts: conf.ConflictOverlay}}}
+		if merged, err := conf.Merge(base, overlay, options); err != nil || merged.String("name") != "app" {
+			t.Errorf("%v: per key strategies should override the global one and null should not conflict: %v", apiName, err)
+		}
+	}
+}
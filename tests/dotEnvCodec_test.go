@@ -0,0 +1,122 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+var (
+	validDotEnvConfigurationFile = testsPath + "validConfiguration.env"
+)
+
+/*
+Try to Load a Configuration from a valid dotenv file
+*/
+func TestDotEnvLoadValidConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.LoadDotEnv(validDotEnvConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.Size() != 9:
+			t.Errorf("%v: Configuration's size should be 9 not %v", apiName, configuration.Size())
+
+		case configuration.String("APP_NAME") != "legacy" || configuration.String("DATABASE_HOST") != "localhost":
+			t.Errorf("%v: APP_NAME and exported DATABASE_HOST should be legacy and localhost", apiName)
+
+		case configuration.Int("DATABASE_PORT") != 5432:
+			t.Errorf("%v: DATABASE_PORT should be 5432 without its comment not %q", apiName, configuration.String("DATABASE_PORT"))
+
+		case configuration.String("SINGLE") != "literal \\n $HOME":
+			t.Errorf("%v: single quoted values should be literal not %q", apiName, configuration.String("SINGLE"))
+
+		case configuration.String("DOUBLE") != "tab\tquote\" dollar$" || configuration.String("MULTI") != "first\nsecond":
+			t.Errorf("%v: double quoted values should be unescaped not %q and %q", apiName, configuration.String("DOUBLE"), configuration.String("MULTI"))
+
+		case !configuration.HasProperty("EMPTY") || configuration.String("EMPTY") != "":
+			t.Errorf("%v: EMPTY should be an empty string", apiName)
+
+		case configuration.String("URL") != "http://example.com/#anchor":
+			t.Errorf("%v: a # without a white space before should be kept not %q", apiName, configuration.String("URL"))
+		}
+	}
+}
+
+/*
+Try to Save a nested Configuration in dotenv format and Load it back
+*/
+func TestDotEnvSaveAndLoad(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1").Add("message", "Hello \"$USER\"\n")
+		if err := api.SaveDotEnv(configuration, testsPath+"save.env.local"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(testsPath + "save.env.local")
+		savedConfiguration, err := api.LoadDotEnv(testsPath + "save.env.local")
+		os.Remove(testsPath + "save.env.local")
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", apiName, err, content)
+		}
+
+		switch {
+
+		case savedConfiguration.String("MESSAGE") != "Hello \"$USER\"\n":
+			t.Errorf("%v: MESSAGE should be escaped and loaded back not %q\n%s", apiName, savedConfiguration.String("MESSAGE"), content)
+
+		case savedConfiguration.Int("DATABASE_POOL_SIZE") != configuration.Int("database.pool.size"):
+			t.Errorf("%v: child Configurations should be flattened in upper case names\n%s", apiName, content)
+		}
+	}
+}
+
+/*
+Try to Load invalid dotenv contents
+*/
+func TestDotEnvLoadInvalidConfiguration(t *testing.T) {
+
+	for _, content := range []string{"NO_SEPARATOR\n", "1KEY=value\n", "KEY=\"unclosed\n", "KEY='value' rest\n"} {
+
+		if err := ioutil.WriteFile(testsPath+"invalid.env", []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conf.Default().LoadDotEnv(testsPath + "invalid.env"); err == nil {
+			t.Errorf("LoadDotEnv() should return an error for %q", content)
+		}
+	}
+	os.Remove(testsPath + "invalid.env")
+}
+
+/*
+Try to Save a loaded Configuration in dotenv format without its RootPath
+*/
+func TestDotEnvSaveWithoutRootPath(t *testing.T) {
+
+	fileName := testsPath + "rootPath.env.local"
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		configuration, err := api.LoadDotEnv(validDotEnvConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		if err := api.SaveDotEnv(configuration, fileName); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(fileName)
+
+		if !configuration.HasProperty(conf.RootPathKey) || strings.Contains(string(content), "ROOTPATH") || !strings.Contains(string(content), "APP_NAME=") {
+			t.Errorf("%v: RootPath should not be saved as a variable\n%s", apiName, content)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"reflect"
+	"testing"
+	"time"
+)
+
+/*
+Try to overlay environment variables converted to the type of the existing values
+*/
+func TestOverlayEnvironment(t *testing.T) {
+
+	environ := []string{"APP_DATABASE_POOL_SIZE=25", "APP_DATABASE_POOL_TIMEOUT=1m", "APP_DEBUG=true",
+		"APP_HOSTS=b, c", "APP_NEW_KEY=value", "DB_URL=postgres://db", "OTHER_DEBUG=false"}
+
+	for apiName, api := range apis {
+
+		base := api.New("app").Add("database.pool.size", 10.0).Add("database.pool_timeout", 5*time.Second).
+			Add("debug", false).Add("hosts", []interface{}{"a"})
+		configuration, err := api.OverlayEnvironment(base, conf.EnvironmentOptions{Prefix: "app", Environ: environ,
+			Mapping: map[string]string{"DB_URL": "database.url"}})
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: environment should be overlaid: %v", apiName, err)
+
+		case configuration.Property("database.pool.size").Value() != 25.0:
+			t.Errorf("%v: numbers should stay float64 not %#v", apiName, configuration.Property("database.pool.size").Value())
+
+		case configuration.Duration("database.pool_timeout") != time.Minute || configuration.Property("debug").Value() != true:
+			t.Errorf("%v: durations and booleans should be converted", apiName)
+
+		case !reflect.DeepEqual(configuration.Property("hosts").Value(), []interface{}{"b", "c"}):
+			t.Errorf("%v: arrays should be split on commas not %#v", apiName, configuration.Property("hosts").Value())
+
+		case configuration.String("new.key") != "value" || configuration.String("database.url") != "postgres://db":
+			t.Errorf("%v: new prefixed and mapped variables should be added as strings", apiName)
+
+		case configuration.Property("database.pool.size").Source() != conf.Source{Kind: conf.SourceEnvironment, Name: "APP_DATABASE_POOL_SIZE"}:
+			t.Errorf("%v: the Source should be the variable not %v", apiName, configuration.Property("database.pool.size").Source())
+
+		case len(configuration.Explain("debug")) != 2 || base.Property("debug").Value() != false:
+			t.Errorf("%v: the environment should be a layer over the unchanged configuration", apiName)
+		}
+	}
+}
+
+/*
+Try to overlay environment variables with another Separator
+*/
+func TestOverlayEnvironmentOptions(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		base := api.New("app").Add("database.pool.size", 10.0).Add("database.pool_timeout", 5*time.Second).Add("debug", false)
+		configuration, err := api.OverlayEnvironment(base, conf.EnvironmentOptions{Prefix: "APP", Separator: "__",
+			Environ: []string{"APP__DEBUG=1", "APP__DATABASE__POOL__SIZE=3", "APP__DATABASE__POOL_TIMEOUT=2s", "PATH=/bin"}})
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: environment should be overlaid: %v", apiName, err)
+
+		case configuration.Property("database.pool.size").Value() != 3.0 || configuration.Duration("database.pool_timeout") != 2*time.Second:
+			t.Errorf("%v: the Separator should join the path's names", apiName)
+
+		case configuration.Property("debug").Value() != true || configuration.HasProperty("path"):
+			t.Errorf("%v: only the prefixed variables should be overlaid", apiName)
+		}
+	}
+}
+
+/*
+Try to overlay environment variables without Prefix over properties named like the process' variables
+*/
+func TestOverlayEnvironmentWithoutPrefix(t *testing.T) {
+
+	environ := []string{"PATH=/bin", "USER=root", "HOME=/root", "ROOTPATH=/", "APP_ROOTPATH=/", "DEBUG=true", "DB_URL=postgres://db"}
+
+	for apiName, api := range apis {
+
+		base := api.New("app").Add("debug", false).Add("path", "/data").Add("user", "app").Add("home", "/home/app").Add(conf.RootPathKey, "tests")
+		configuration, err := api.OverlayEnvironment(base, conf.EnvironmentOptions{Environ: environ,
+			Mapping: map[string]string{"DB_URL": "database.url", "ROOTPATH": conf.RootPathKey}})
+		prefixed, prefixedErr := api.OverlayEnvironment(base, conf.EnvironmentOptions{Prefix: "APP", Environ: environ})
+
+		switch {
+
+		case err != nil || prefixedErr != nil:
+			t.Errorf("%v: environment should be overlaid: %v %v", apiName, err, prefixedErr)
+
+		case configuration.String("path") != "/data" || configuration.String("user") != "app" || configuration.String("home") != "/home/app":
+			t.Errorf("%v: the process' variables should never be applied without Prefix", apiName)
+
+		case configuration.Property("debug").Value() != false || configuration.String("database.url") != "postgres://db":
+			t.Errorf("%v: without Prefix only the mapped variables should be applied", apiName)
+
+		case configuration.String(conf.RootPathKey) != "tests" || prefixed.String(conf.RootPathKey) != "tests" || prefixed.HasProperty("rootpath"):
+			t.Errorf("%v: RootPath should never be overlaid", apiName)
+		}
+	}
+}
+
+/*
+Try to overlay environment variables which can't be converted
+*/
+func TestOverlayEnvironmentError(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		_, err := api.OverlayEnvironment(api.New("app").Add("database.pool.size", 10.0), conf.EnvironmentOptions{Prefix: "APP",
+			Environ: []string{"APP_DATABASE_POOL_SIZE=many"}})
+
+		if err == nil {
+			t.Errorf("%v: invalid numbers should fail", apiName)
+		}
+
+		base := api.New("app").Add("database.host", "localhost")
+		if _, err := api.OverlayEnvironment(base, conf.EnvironmentOptions{Prefix: "APP", Environ: []string{"APP_DATABASE=postgres://db"}}); err == nil {
+			t.Errorf("%v: a variable should not replace a child Configuration", apiName)
+		}
+		if _, err := api.OverlayEnvironment(base, conf.EnvironmentOptions{Environ: []string{"DB=postgres://db"}, Mapping: map[string]string{"DB": "database"}}); err == nil {
+			t.Errorf("%v: a mapped variable should not replace a child Configuration", apiName)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package eliteConfiguration_test
+
+import (
+	"flag"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+/*
+Try to generate a FlagSet from a Configuration
+*/
+func TestFlagSet(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		flags := conf.FlagSet(api.New("app").Add("database.pool.size", 10.0).Add("debug", false).Add("hosts", []interface{}{"a"}), "app", flag.ContinueOnError)
+
+		switch {
+
+		case flags.Lookup("database.pool.size") == nil || flags.Lookup("database.pool.size").DefValue != "10":
+			t.Errorf("%v: nested properties should be flags with their Value as default", apiName)
+
+		case flags.Lookup("debug") == nil || flags.Lookup("hosts") == nil || flags.Lookup("database") != nil:
+			t.Errorf("%v: only the properties which aren't Configurations should be flags", apiName)
+
+		case flags.Lookup("debug").Value.(flag.Getter).Get() != false:
+			t.Errorf("%v: the flags should hold the typed Values", apiName)
+		}
+	}
+}
+
+/*
+Try to apply parsed flags over a Configuration
+*/
+func TestApplyFlags(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		base := api.New("app").Add("database.pool.size", 10.0).Add("database.pool_timeout", 5*time.Second).
+			Add("debug", false).Add("hosts", []interface{}{"a"}).Add("name", "app")
+		flags := conf.FlagSet(base, "app", flag.ContinueOnError)
+		err := flags.Parse([]string{"-debug", "--database.pool.size=20", "-database.pool_timeout", "1m", "-hosts=b,c", "extra"})
+		configuration := conf.ApplyFlags(base, flags)
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: flags should be parsed: %v", apiName, err)
+
+		case configuration.Property("database.pool.size").Value() != 20.0 || configuration.Duration("database.pool_timeout") != time.Minute:
+			t.Errorf("%v: flags should be converted to the type of the Values", apiName)
+
+		case configuration.Property("debug").Value() != true || !reflect.DeepEqual(configuration.Property("hosts").Value(), []interface{}{"b", "c"}):
+			t.Errorf("%v: boolean flags and lists should be applied", apiName)
+
+		case configuration.String("name") != "app" || len(configuration.Explain("name")) != 1:
+			t.Errorf("%v: flags not set should not be applied", apiName)
+
+		case configuration.Property("debug").Source() != conf.Source{Kind: conf.SourceFlag, Name: "-debug"}:
+			t.Errorf("%v: the Source should be the flag not %v", apiName, configuration.Property("debug").Source())
+
+		case base.Property("debug").Value() != false || !reflect.DeepEqual(flags.Args(), []string{"extra"}):
+			t.Errorf("%v: the flags should be a layer over the unchanged configuration", apiName)
+		}
+	}
+}
+
+/*
+Try to parse flags which can't be converted
+*/
+func TestApplyFlagsError(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		flags := conf.FlagSet(api.New("app").Add("database.pool.size", 10.0), "app", flag.ContinueOnError)
+		flags.SetOutput(ioutil.Discard)
+
+		if err := flags.Parse([]string{"-database.pool.size=many"}); err == nil {
+			t.Errorf("%v: invalid numbers should fail", apiName)
+		}
+	}
+}
+
+/*
+Try to generate a FlagSet from a Configuration with a dotted key and the same nested path
+*/
+func TestFlagSetDuplicatedPath(t *testing.T) {
+
+	fileName := testsPath + "duplicatedPath.json"
+	ioutil.WriteFile(fileName, []byte(`{"database": {"host": "b", "port": 5432}, "database.host": "a"}`), 0600)
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(fileName)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		flags := conf.FlagSet(configuration, "app", flag.ContinueOnError)
+		if err := flags.Parse([]string{"-database.host=c"}); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		configuration = conf.ApplyFlags(configuration, flags)
+
+		switch {
+
+		case flags.Lookup("database.host") == nil || flags.Lookup("database.port") == nil:
+			t.Errorf("%v: the duplicated path should be defined once", apiName)
+
+		case flags.Lookup("database.host").DefValue != "a":
+			t.Errorf("%v: the literal key should be the default not %v", apiName, flags.Lookup("database.host").DefValue)
+
+		case configuration.String("database.host") != "c" || configuration.Property("database.host").Source().Kind != conf.SourceFlag:
+			t.Errorf("%v: the flag should be applied not %v", apiName, configuration.Property("database.host").Value())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+/*
+Try to Load Configurations from a fs.FS, with a RootPath relative to the FS
+*/
+func TestLoadFSConfiguration(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"config/defaults.yaml": &fstest.MapFile{Data: []byte("database:\n  host: localhost\n  port: 5432\n")},
+	}
+
+	for apiName, api := range apis {
+
+		configuration, err := api.LoadFS(fsys, "config/defaults.yaml")
+		switch {
+
+		case err != nil:
+			t.Fatalf("%v: %v", apiName, err)
+
+		case configuration.String("database.host") != "localhost" || configuration.Int("database.port") != 5432:
+			t.Errorf("%v: the YAML file of the FS should be loaded", apiName)
+
+		case configuration.String(conf.RootPathKey) != "config":
+			t.Errorf("%v: RootPath should be relative to the FS not %v", apiName, configuration.String(conf.RootPathKey))
+		}
+
+		configuration, err = api.LoadFS(os.DirFS(testsPath), "nestedConfiguration.json")
+		switch {
+
+		case err != nil:
+			t.Fatalf("%v: %v", apiName, err)
+
+		case configuration.Int("database.pool.size") != 10 || configuration.String(conf.RootPathKey) != ".":
+			t.Errorf("%v: the JSON file of the directory FS should be loaded with the RootPath \".\"", apiName)
+		}
+
+		if _, err := api.LoadFS(fsys, "config/missing.json"); err == nil {
+			t.Errorf("%v: LoadFS should return an error for a missing file", apiName)
+		}
+	}
+}
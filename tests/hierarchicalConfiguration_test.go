@@ -0,0 +1,133 @@
+package eliteConfiguration_test
+
+import (
+	"bytes"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+var (
+	nestedConfigurationFile           = testsPath + "nestedConfiguration.json"
+	validNestedImmutableConfiguration = conf.Immutable().New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1")
+)
+
+/*
+Try to Load a Configuration with nested Configurations and access them with dotted paths
+*/
+func TestNestedLoadConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(nestedConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.Size() != 3:
+			t.Errorf("%v: Configuration's size should be 3 not %v", apiName, configuration.Size())
+
+		case configuration.Int("database.pool.size") != 10:
+			t.Errorf("%v: Int(\"database.pool.size\") should be 10 not %v", apiName, configuration.Int("database.pool.size"))
+
+		case configuration.Sub("database").String("host") != "localhost":
+			t.Errorf("%v: Sub(\"database\").String(\"host\") should be \"localhost\" not \"%v\"", apiName, configuration.Sub("database").String("host"))
+
+		case configuration.Sub("database.pool").Name() != "pool":
+			t.Errorf("%v: Sub(\"database.pool\").Name() should be \"pool\" not \"%v\"", apiName, configuration.Sub("database.pool").Name())
+
+		case !configuration.HasProperty("database.pool") || configuration.HasProperty("database.pool.max"):
+			t.Errorf("%v: HasProperty() should find \"database.pool\" and not \"database.pool.max\"", apiName)
+		}
+	}
+}
+
+/*
+Try to Load plain JSON objects as nested Configurations
+*/
+func TestNestedLoadPlainObjects(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(bindingConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		if configuration.Int("database.port") != 5432 || configuration.Sub("limits").Size() != 2 {
+			t.Errorf("%v: plain JSON objects should be loaded as nested Configurations", apiName)
+		}
+	}
+}
+
+/*
+Try to Add and Remove Properties with dotted paths
+*/
+func TestNestedAddAndRemove(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1")
+		configuration = configuration.Add("database.pool.max", 20).Remove("database.host")
+
+		switch {
+
+		case configuration.Size() != 2:
+			t.Errorf("%v: Configuration's size should be 2 not %v", apiName, configuration.Size())
+
+		case configuration.Sub("database.pool").Size() != 2:
+			t.Errorf("%v: Sub(\"database.pool\").Size() should be 2 not %v", apiName, configuration.Sub("database.pool").Size())
+
+		case configuration.HasProperty("database.host"):
+			t.Errorf("%v: Remove(\"database.host\") should have removed the nested Property", apiName)
+		}
+	}
+}
+
+/*
+Check that an existing Property named with a dotted name wins over the nested ones
+*/
+func TestNestedDottedName(t *testing.T) {
+
+	configuration := conf.Default().New("dotted").AddProperty(conf.Default().New("").Property("database.host").WithDefault("dotted")).Add("database.host", "changed")
+
+	if configuration.Size() != 1 || configuration.String("database.host") != "changed" {
+		t.Errorf("Add(\"database.host\") should have replaced the dotted Property, not created a nested one")
+	}
+}
+
+/*
+Check the immutability of nested Configurations
+*/
+func TestNestedImmutability(t *testing.T) {
+
+	validNestedImmutableConfiguration.Add("database.host", "changed").Remove("database.pool.size")
+
+	if validNestedImmutableConfiguration.String("database.host") != "localhost" || !validNestedImmutableConfiguration.HasProperty("database.pool.size") {
+		t.Error("Nested Configurations should be immutable")
+	}
+}
+
+/*
+Try to Save a Configuration with nested Configurations and compare result file with valid
+*/
+func TestNestedSaveConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		if err := api.Save(api.New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1"), testsPath+"save.json"); err != nil {
+			t.Errorf("%v: Save() should not return an error", apiName)
+		}
+
+		if jsonContent, err := ioutil.ReadFile(testsPath + "save.json"); err == nil {
+			if compareContent, _ := ioutil.ReadFile(nestedConfigurationFile); !bytes.Equal(jsonContent, compareContent) {
+				t.Errorf("%v: Save(): the JSON content saved is not equal to nestedConfiguration.json file", apiName)
+			}
+		}
+
+		os.Remove(testsPath + "save.json")
+	}
+}
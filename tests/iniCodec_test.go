@@ -0,0 +1,126 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	validINIConfigurationFile = testsPath + "validConfiguration.ini"
+)
+
+/*
+Try to Load a Configuration from a valid INI file
+*/
+func TestINILoadValidConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validINIConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case !reflect.DeepEqual(configuration.Keys(), []string{"name", "Key1", "quoted", "database", "cache", conf.RootPathKey}):
+			t.Errorf("%v: Keys should keep the file's order not %v", apiName, configuration.Keys())
+
+		case configuration.String("Key1") != "Value1" || configuration.String("quoted") != "  padded  ":
+			t.Errorf("%v: Key1 and quoted should be Value1 and \"  padded  \"", apiName)
+
+		case configuration.String("database.host") != "localhost" || configuration.Int("database.port") != 5432 || !configuration.Bool("database.enabled"):
+			t.Errorf("%v: the database section should be loaded as a child Configuration", apiName)
+
+		case configuration.Int("database.pool.size") != 10 || configuration.Duration("database.pool.timeout").Seconds() != 5:
+			t.Errorf("%v: the database.pool section should be loaded as a nested child Configuration", apiName)
+
+		case !reflect.DeepEqual(configuration.StringSlice("cache.hosts"), []string{"host1", "host2"}):
+			t.Errorf("%v: cache.hosts should be [host1 host2] not %v", apiName, configuration.StringSlice("cache.hosts"))
+		}
+	}
+}
+
+/*
+Try to Save a Configuration in INI and check the properties' order is preserved
+*/
+func TestINISaveAndLoad(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validINIConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		configuration = configuration.Remove(conf.RootPathKey).Add("database.pool.max", 20).Add("added", []string{"a", "b"})
+		if err := api.Save(configuration, testsPath+"save.ini"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(testsPath + "save.ini")
+		savedConfiguration, err := api.Load(testsPath + "save.ini")
+		os.Remove(testsPath + "save.ini")
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", apiName, err, content)
+		}
+
+		expectedContent := "name = legacy\nKey1 = Value1\nquoted = \"  padded  \"\nadded = a,b\n\n" +
+			"[database]\nhost = localhost\nport = 5432\nenabled = true\n\n" +
+			"[database.pool]\nsize = 10\ntimeout = 5s\nmax = 20\n\n" +
+			"[cache]\nhosts = host1, host2\n"
+		switch {
+
+		case string(content) != expectedContent:
+			t.Errorf("%v: saved content should be\n%s\nnot\n%s", apiName, expectedContent, content)
+
+		case savedConfiguration.String("quoted") != "  padded  " || savedConfiguration.Int("database.pool.max") != 20:
+			t.Errorf("%v: saved values should be loaded back", apiName)
+		}
+	}
+}
+
+/*
+Try to Load invalid INI contents
+*/
+func TestINILoadInvalidConfiguration(t *testing.T) {
+
+	for _, content := range []string{"[section\n", "[]\n", "no separator\n", "key = 1\n[key]\n", "[key]\n[.child]\n", "key = 1\nkey = 2\n", "[a]\nkey = 1\n[b]\n[a]\nkey = 2\n"} {
+
+		if err := ioutil.WriteFile(testsPath+"invalid.ini", []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conf.Default().Load(testsPath + "invalid.ini"); err == nil {
+			t.Errorf("Load() should return an error for %q", content)
+		}
+	}
+	os.Remove(testsPath + "invalid.ini")
+}
+
+/*
+Try to Load an INI content defining a key twice in a section, the error should give the line of the second one
+*/
+func TestINILoadDuplicatedKey(t *testing.T) {
+
+	fileName := testsPath + "duplicated.ini"
+	defer os.Remove(fileName)
+	if err := ioutil.WriteFile(fileName, []byte("[database]\nhost = a\nport = 5432\nhost = b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for apiName, api := range apis {
+
+		_, err := api.Load(fileName)
+		switch {
+
+		case err == nil:
+			t.Errorf("%v: Load() should return an error for a duplicated key", apiName)
+
+		case !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "host"):
+			t.Errorf("%v: the error should give the line and the key not %v", apiName, err)
+		}
+	}
+}
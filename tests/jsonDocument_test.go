@@ -0,0 +1,132 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+var (
+	commentedConfigurationFile = testsPath + "commentedConfiguration.json"
+)
+
+/*
+Try to Save a modified Configuration with a Preserve JSONCodec and check only the modified properties' text changes
+*/
+func TestPreserveJSONSaveConfiguration(t *testing.T) {
+
+	conf.RegisterCodec(".preserved", conf.JSONCodec{Preserve: true})
+	original, _ := ioutil.ReadFile(commentedConfigurationFile)
+	fileName := testsPath + "save.preserved"
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		ioutil.WriteFile(fileName, original, 0600)
+		configuration, err := api.Load(fileName)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		// Unmodified Configuration
+		if err := api.Save(configuration, fileName); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		if content, _ := ioutil.ReadFile(fileName); string(content) != string(original) {
+			t.Errorf("%v: an unmodified Configuration should be saved unchanged not\n%s", apiName, content)
+		}
+
+		// One modified property
+		configuration = configuration.Add("database.port", 5433)
+		if err := api.Save(configuration, fileName); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		expected := strings.Replace(string(original), "\"value\": 5432", "\"value\": 5433", 1)
+		if content, _ := ioutil.ReadFile(fileName); string(content) != expected {
+			t.Errorf("%v: only database.port should change, content is\n%s", apiName, content)
+		}
+
+		// Removed and added properties
+		configuration = configuration.Remove("obsolete").Remove("Key2").Add("Key3", "Value3")
+		if err := api.Save(configuration, fileName); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(fileName)
+		expected = strings.Replace(expected, "    },\n    \"obsolete\": {\"name\": \"obsolete\", \"value\": true},\n    \"Key2\": {\"name\": \"Key2\", \"value\": [1, 2, 3]}\n",
+			"    },\n    \"Key3\": {\n      \"name\": \"Key3\",\n      \"value\": \"Value3\"\n    }\n", 1)
+		if string(content) != expected {
+			t.Errorf("%v: obsolete and Key2 should be replaced by Key3, content is\n%s\nnot\n%s", apiName, content, expected)
+		}
+
+		savedConfiguration, err := api.Load(fileName)
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: %v", apiName, err)
+
+		case savedConfiguration.String("Key3") != "Value3" || savedConfiguration.Int("database.port") != 5433 || savedConfiguration.HasProperty("obsolete"):
+			t.Errorf("%v: the saved Configuration should be loaded back", apiName)
+		}
+	}
+}
+
+/*
+Try to Save with a Preserve JSONCodec given to SaveWith, keeping the comments of the kept properties and the
+indentation of the document
+*/
+func TestPreserveJSONSaveWith(t *testing.T) {
+
+	fileName := testsPath + "saveWith.json"
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		for _, test := range []struct {
+			original string
+			modify   func(configuration conf.Configuration) conf.Configuration
+			expected string
+		}{
+			{"{\n  \"a\": 1, // about a\n  \"b\": 2 // about b\n}\n",
+				func(configuration conf.Configuration) conf.Configuration { return configuration.Remove("b") },
+				"{\n  \"a\": 1 // about a\n}\n"},
+			{"{\n  \"a\": 1, // about a\n  \"b\": 2 // about b\n}\n",
+				func(configuration conf.Configuration) conf.Configuration { return configuration.Add("c", 3) },
+				"{\n  \"a\": 1, // about a\n  \"b\": 2, // about b\n  \"c\": 3\n}\n"},
+			{"{\n    \"a\": 1,\n    \"d\": {\n        \"x\": 1\n    }\n}\n",
+				func(configuration conf.Configuration) conf.Configuration {
+					return configuration.Add("d.y", 2).Add("e", map[string]interface{}{"k": 1})
+				},
+				"{\n    \"a\": 1,\n    \"d\": {\n        \"x\": 1,\n        \"y\": 2\n    },\n    \"e\": {\n        \"k\": 1\n    }\n}\n"},
+			{"{\n\t\"a\": 1\n}\n",
+				func(configuration conf.Configuration) conf.Configuration {
+					return configuration.Add("c", map[string]interface{}{"k": 1})
+				},
+				"{\n\t\"a\": 1,\n\t\"c\": {\n\t\t\"k\": 1\n\t}\n}\n"},
+			{"{\"a\": 1, \"b\": 2}\n",
+				func(configuration conf.Configuration) conf.Configuration { return configuration.Add("c", 3) },
+				"{\"a\": 1, \"b\": 2, \"c\": 3}\n"},
+			{"{\"a\": 1, \"b\": 2}\n",
+				func(configuration conf.Configuration) conf.Configuration { return configuration.Remove("a") },
+				"{\"b\": 2}\n"},
+			{"{\n  \"a\": 1, \"b\": 2, \"c\": 3\n}\n",
+				func(configuration conf.Configuration) conf.Configuration {
+					return configuration.Remove("a").Remove("b")
+				},
+				"{\n  \"c\": 3\n}\n"},
+		} {
+			ioutil.WriteFile(fileName, []byte(test.original), 0600)
+			configuration, err := api.Load(fileName)
+			if err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+			if err := api.SaveWith(test.modify(configuration), fileName, conf.JSONCodec{Preserve: true}); err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+			if content, _ := ioutil.ReadFile(fileName); string(content) != test.expected {
+				t.Errorf("%v: content should be\n%s\nnot\n%s", apiName, test.expected, content)
+			}
+		}
+	}
+}
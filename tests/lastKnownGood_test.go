@@ -0,0 +1,108 @@
+package eliteConfiguration_test
+
+import (
+	"errors"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+/*
+portValidator refuse the Configurations without a valid port
+*/
+func portValidator(configuration conf.Configuration) error {
+
+	if port, err := configuration.IntValue("port"); err != nil || port <= 0 {
+		return errors.New("port should be a positive number")
+	}
+	return nil
+}
+
+/*
+Try to Validate Configurations
+*/
+func TestValidateConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		hostValidator := func(configuration conf.Configuration) error {
+			if !configuration.HasProperty("host") {
+				return errors.New("host is required")
+			}
+			return nil
+		}
+
+		switch {
+
+		case conf.Validate(api.New("").Add("host", "localhost").Add("port", 80), hostValidator, portValidator) != nil:
+			t.Errorf("%v: a valid Configuration should be accepted", apiName)
+
+		case conf.Validate(api.New("").Add("port", 0), hostValidator, portValidator) == nil:
+			t.Errorf("%v: an invalid Configuration should be refused", apiName)
+		}
+	}
+
+	if name := conf.LastKnownGoodName("config/app.json"); name != "config/app.last-known-good.json" {
+		t.Errorf("the last known good copy should keep the extension not %v", name)
+	}
+}
+
+/*
+Try to Reload invalid files with Validators and a last known good copy
+*/
+func TestReloadLastKnownGood(t *testing.T) {
+
+	fileName := testsPath + "watched.good.json"
+	copyName := conf.LastKnownGoodName(fileName)
+	defer os.Remove(fileName)
+	defer os.Remove(copyName)
+
+	ioutil.WriteFile(fileName, []byte(`{"host": "localhost", "port": 5432}`), 0600)
+	options := conf.WatchOptions{Poll: true, PollInterval: time.Hour, Validators: []conf.Validator{portValidator}, LastKnownGood: true}
+
+	watcher, err := conf.Watch(fileName, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := ioutil.ReadFile(copyName); err != nil || string(content) != `{"host": "localhost", "port": 5432}` || watcher.StartError() != nil {
+		t.Error("the valid file should be copied as the last known good")
+	}
+
+	// Invalid files are reported and the previous Configuration is kept
+	for _, content := range []string{`{"host": "db", "port": 0}`, `{"host": `} {
+		ioutil.WriteFile(fileName, []byte(content), 0600)
+		var reported error
+		watcher.OnError(func(err error) { reported = err })
+
+		switch err := watcher.Reload(); {
+
+		case err == nil || reported == nil:
+			t.Errorf("%v should be reported", content)
+
+		case watcher.Configuration().String("host") != "localhost":
+			t.Errorf("%v should not be applied", content)
+		}
+	}
+	watcher.Close()
+
+	// A restart on an invalid file uses the last known good copy
+	watcher, err = conf.Watch(fileName, options)
+	switch {
+
+	case err != nil:
+		t.Fatalf("Watch() should start on the last known good copy: %v", err)
+
+	case watcher.StartError() == nil || watcher.Configuration().Int("port") != 5432:
+		t.Error("the last known good copy should be used and the error of the file kept")
+	}
+	watcher.Close()
+
+	if _, err := conf.Watch(fileName, conf.WatchOptions{Validators: options.Validators}); err == nil {
+		t.Error("Watch() should fail on an invalid file without LastKnownGood")
+	}
+	if configuration, err := conf.LoadLastKnownGood(fileName); err != nil || configuration.String("host") != "localhost" {
+		t.Error("LoadLastKnownGood() should load the copy")
+	}
+}
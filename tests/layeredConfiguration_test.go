@@ -0,0 +1,76 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"reflect"
+	"testing"
+)
+
+/*
+Try to resolve Values through the layers of a Layered Configuration
+*/
+func TestLayeredConfigurationValues(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		defaults := api.New("defaults").Add("database.host", "localhost").Add("database.port", 5432).Add("debug", false)
+		file := api.New("file").Add("database.host", "db.example.com").Add("Key1", "Value1")
+		configuration := conf.Layered(defaults, file, api.New("").Add("debug", true))
+
+		switch {
+
+		case configuration.Name() != "file":
+			t.Errorf("%v: Name should be the one of the highest named layer not %v", apiName, configuration.Name())
+
+		case configuration.String("database.host") != "db.example.com" || !configuration.Bool("debug"):
+			t.Errorf("%v: the highest layers should win", apiName)
+
+		case configuration.Int("database.port") != 5432 || !configuration.HasProperty("database.port"):
+			t.Errorf("%v: lower layers values should be found", apiName)
+
+		case configuration.Size() != 3 || !reflect.DeepEqual(configuration.Keys(), []string{"database", "debug", "Key1"}):
+			t.Errorf("%v: Size and Keys should reflect the merged view not %v", apiName, configuration.Keys())
+
+		case configuration.Sub("database").Size() != 2 || configuration.Sub("database").String("host") != "db.example.com":
+			t.Errorf("%v: child Configurations of several layers should be layered", apiName)
+
+		case configuration.Property("missing").Value() != nil || configuration.HasProperty("missing"):
+			t.Errorf("%v: missing properties should not be found", apiName)
+		}
+	}
+}
+
+/*
+Try to modify a Layered Configuration
+*/
+func TestLayeredConfigurationModifications(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		newLayered := func() conf.Configuration {
+			return conf.Layered(api.New("defaults").Add("database.host", "localhost").Add("database.port", 5432).Add("debug", false),
+				api.New("file").Add("database.host", "db.example.com").Add("Key1", "Value1"), api.New("").Add("debug", true))
+		}
+
+		configuration := newLayered()
+		configuration = configuration.Add("database.port", 6543).Remove("Key1").Remove("database.host")
+
+		switch {
+
+		case configuration.Int("database.port") != 6543:
+			t.Errorf("%v: Add should set the Value in the highest layer", apiName)
+
+		case configuration.HasProperty("Key1") || configuration.HasProperty("database.host"):
+			t.Errorf("%v: Remove should remove the Property from all the layers", apiName)
+
+		case len(conf.Layers(configuration)) != 3 || conf.Layers(configuration)[2].Int("database.port") != 6543:
+			t.Errorf("%v: Layers should return the 3 layers with the modified highest one", apiName)
+		}
+
+		marshalled, err := api.Marshal(newLayered())
+		savedConfiguration, _ := api.Parse(marshalled)
+		if err != nil || savedConfiguration.String("database.host") != "db.example.com" || savedConfiguration.Int("database.port") != 5432 {
+			t.Errorf("%v: the merged view should be saved not\n%s", apiName, marshalled)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"sync"
+	"testing"
+)
+
+/*
+Try to Get, Swap and CompareAndSwap the Configuration of a Live
+*/
+func TestLiveConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		first := api.New("first").Add("Key1", "Value1")
+		second := api.New("second").Add("Key1", "Value2")
+		live := conf.NewLive(first)
+
+		switch {
+
+		case live.Get().Name() != "first":
+			t.Errorf("%v: Get() should return the initial Configuration", apiName)
+
+		case live.Swap(second).Name() != "first" || live.Get().Name() != "second":
+			t.Errorf("%v: Swap() should hold the new Configuration and return the previous one", apiName)
+
+		case live.CompareAndSwap(first, first) || live.Get().Name() != "second":
+			t.Errorf("%v: CompareAndSwap() should fail when the old Configuration isn't held anymore", apiName)
+
+		case !live.CompareAndSwap(live.Get(), first) || live.Get().Name() != "first":
+			t.Errorf("%v: CompareAndSwap() should succeed with the held Configuration", apiName)
+
+		}
+	}
+
+	first := conf.Immutable().New("first").Add("Key1", "Value1")
+	live := conf.NewLive(first)
+	if live.CompareAndSwap(first.Add("Key2", "Value2"), first) || live.CompareAndSwap(conf.Immutable().New("first").Add("Key1", "Value1"), first) {
+		t.Error("CompareAndSwap() should compare snapshots, not equal values")
+	}
+	if live = conf.NewLive(conf.Layered(first)); !live.CompareAndSwap(conf.Layered(first), first) {
+		t.Error("CompareAndSwap() should accept Layered Configurations of the same layers")
+	}
+}
+
+/*
+Try to Update a Live concurrently, no update should be lost
+*/
+func TestLiveConfigurationUpdate(t *testing.T) {
+
+	live := conf.NewLive(conf.Immutable().New("counter").Add("count", 0))
+
+	var waitGroup sync.WaitGroup
+	for goroutine := 0; goroutine < 8; goroutine++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for increment := 0; increment < 100; increment++ {
+				live.Update(func(configuration conf.Configuration) conf.Configuration {
+					return configuration.Add("count", configuration.Int("count")+1)
+				})
+				if live.Get().Name() != "counter" {
+					t.Error("readers should always get a consistent Configuration")
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if live.Get().Int("count") != 800 {
+		t.Errorf("all the updates should be applied, count is %v", live.Get().Int("count"))
+	}
+}
+
+/*
+Try to use a zero Live, it should hold an empty Configuration
+*/
+func TestLiveConfigurationZeroValue(t *testing.T) {
+
+	var live conf.Live
+	empty := live.Get()
+
+	switch {
+
+	case empty == nil || empty.Size() != 0 || live.Swap(empty) == nil:
+		t.Error("a zero Live should hold an empty Configuration")
+
+	case live.Update(func(configuration conf.Configuration) conf.Configuration { return configuration.Add("Key1", "Value1") }).String("Key1") != "Value1":
+		t.Error("a zero Live should be updated")
+
+	case live.Get().String("Key1") != "Value1":
+		t.Errorf("Get() should return the updated Configuration not %v", live.Get().Keys())
+	}
+
+	var other conf.Live
+	if !other.CompareAndSwap(other.Get(), conf.Immutable().New("first")) || other.Get().Name() != "first" {
+		t.Error("CompareAndSwap() should succeed with the Configuration held by a zero Live")
+	}
+}
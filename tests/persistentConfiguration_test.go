@@ -0,0 +1,163 @@
+package eliteConfiguration_test
+
+import (
+	"fmt"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"reflect"
+	"testing"
+)
+
+/*
+Try to build, modify and read a large immutable Configuration, the previous versions should be unchanged
+*/
+func TestLargeImmutableConfiguration(t *testing.T) {
+
+	const size = 5000
+	configuration := conf.Immutable().New("large")
+	keys := make([]string, 0, size)
+	for index := 0; index < size; index++ {
+		keys = append(keys, fmt.Sprintf("key%v", index))
+		configuration = configuration.Add(keys[index], index)
+	}
+	built := configuration
+
+	// Replace the even properties and remove one third of them
+	for index := 0; index < size; index += 2 {
+		configuration = configuration.Add(keys[index], -index)
+	}
+	var remainingKeys []string
+	for index := 0; index < size; index++ {
+		if index%3 == 0 {
+			configuration = configuration.Remove(keys[index])
+		} else {
+			remainingKeys = append(remainingKeys, keys[index])
+		}
+	}
+
+	switch {
+
+	case built.Size() != size || !reflect.DeepEqual(built.Keys(), keys) || built.Int("key42") != 42:
+		t.Error("the built Configuration should not be modified by the next ones")
+
+	case configuration.Size() != len(remainingKeys) || !reflect.DeepEqual(configuration.Keys(), remainingKeys):
+		t.Errorf("replaced properties should keep their order, size is %v", configuration.Size())
+
+	case configuration.Int("key2") != -2 || configuration.Int("key1") != 1 || configuration.HasProperty("key3"):
+		t.Error("the properties should be replaced and removed")
+
+	case configuration.Remove("missing").Size() != configuration.Size():
+		t.Error("removing a missing property should not change the Configuration")
+	}
+}
+
+/*
+copyOnWriteConfiguration is the previous immutable implementation, copying all the properties on each Add
+*/
+type copyOnWriteConfiguration struct {
+	properties map[string]interface{}
+	order      []string
+}
+
+/*
+add return a copy of the configuration with the value
+*/
+func (configuration copyOnWriteConfiguration) add(name string, value interface{}) copyOnWriteConfiguration {
+
+	properties := make(map[string]interface{}, len(configuration.properties)+1)
+	for key, existingValue := range configuration.properties {
+		properties[key] = existingValue
+	}
+	order := configuration.order
+	if _, exist := properties[name]; !exist {
+		order = append(append(make([]string, 0, len(order)+1), order...), name)
+	}
+	properties[name] = value
+	return copyOnWriteConfiguration{properties: properties, order: order}
+}
+
+/*
+benchmarkSizes are the numbers of properties of the benchmarked Configurations
+*/
+var benchmarkSizes = []int{10, 100, 1000, 10000}
+
+/*
+benchmarkKeys return the names of size properties
+*/
+func benchmarkKeys(size int) []string {
+
+	keys := make([]string, size)
+	for index := range keys {
+		keys[index] = fmt.Sprintf("key%v", index)
+	}
+	return keys
+}
+
+/*
+Benchmark the build of Configurations of several sizes with the persistent trie of the immutable API
+*/
+func BenchmarkImmutableAdd(b *testing.B) {
+
+	for _, size := range benchmarkSizes {
+		keys := benchmarkKeys(size)
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			for iteration := 0; iteration < b.N; iteration++ {
+				configuration := conf.Immutable().New("")
+				for index, key := range keys {
+					configuration = configuration.Add(key, index)
+				}
+			}
+		})
+	}
+}
+
+/*
+Benchmark the build of Configurations of several sizes with the previous copy-on-write
+*/
+func BenchmarkCopyOnWriteAdd(b *testing.B) {
+
+	for _, size := range benchmarkSizes {
+		keys := benchmarkKeys(size)
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			for iteration := 0; iteration < b.N; iteration++ {
+				configuration := copyOnWriteConfiguration{}
+				for index, key := range keys {
+					configuration = configuration.add(key, index)
+				}
+			}
+		})
+	}
+}
+
+/*
+Benchmark the build of Configurations of several sizes with the mutable API
+*/
+func BenchmarkMutableAdd(b *testing.B) {
+
+	for _, size := range benchmarkSizes {
+		keys := benchmarkKeys(size)
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			for iteration := 0; iteration < b.N; iteration++ {
+				configuration := conf.Mutable().New("")
+				for index, key := range keys {
+					configuration.Add(key, index)
+				}
+			}
+		})
+	}
+}
+
+/*
+Benchmark the access to a property of a large immutable Configuration
+*/
+func BenchmarkImmutableValue(b *testing.B) {
+
+	configuration := conf.Immutable().New("")
+	keys := benchmarkKeys(benchmarkSizes[len(benchmarkSizes)-1])
+	for index, key := range keys {
+		configuration = configuration.Add(key, index)
+	}
+	b.ResetTimer()
+	for iteration := 0; iteration < b.N; iteration++ {
+		configuration.Int(keys[iteration%len(keys)])
+	}
+}
@@ -0,0 +1,184 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	plainConfigurationFile = testsPath + "plainConfiguration.json"
+)
+
+/*
+Try to Load a Configuration from a plain JSON object without the name/value envelopes
+*/
+func TestPlainJSONLoadConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(plainConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.Name() != "plainConfiguration" || configuration.HasProperty("name"):
+			t.Errorf("%v: \"name\" should be the Name not a property, Name is %v", apiName, configuration.Name())
+
+		case !reflect.DeepEqual(configuration.Keys(), []string{"Key1", "database", "hosts", conf.RootPathKey}):
+			t.Errorf("%v: Keys should keep the file's order not %v", apiName, configuration.Keys())
+
+		case configuration.String("Key1") != "Value1" || configuration.String("database.host") != "localhost" || configuration.Int("database.pool.size") != 10:
+			t.Errorf("%v: plain JSON objects should be loaded as child Configurations", apiName)
+
+		case !reflect.DeepEqual(configuration.StringSlice("hosts"), []string{"host1", "host2"}):
+			t.Errorf("%v: hosts should be [host1 host2] not %v", apiName, configuration.StringSlice("hosts"))
+		}
+	}
+}
+
+/*
+Try to Save a Configuration in both shapes and Load it back
+*/
+func TestPlainJSONSaveConfiguration(t *testing.T) {
+
+	conf.RegisterCodec(".plain", conf.JSONCodec{Plain: true})
+	defer os.Remove(testsPath + "save.plain")
+	defer os.Remove(testsPath + "save.json")
+
+	for apiName, api := range apis {
+
+		configuration := api.New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1").Add("hosts", []string{"host1", "host2"})
+		if err := api.Save(configuration, testsPath+"save.plain"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(testsPath + "save.plain")
+		expectedContent := "{\n  \"name\": \"nestedConfiguration\",\n  \"database\": {\n    \"host\": \"localhost\",\n    \"pool\": {\n      \"size\": 10\n    }\n  },\n" +
+			"  \"Key1\": \"Value1\",\n  \"hosts\": [\n    \"host1\",\n    \"host2\"\n  ]\n}"
+		if string(content) != expectedContent {
+			t.Errorf("%v: saved content should be\n%s\nnot\n%s", apiName, expectedContent, content)
+		}
+
+		// Plain files are detected by the default JSONCodec, which saves them in the envelope shape
+		plainConfiguration, err := api.Load(testsPath + "save.plain")
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		if plainConfiguration.Name() != "nestedConfiguration" || plainConfiguration.HasProperty("name") {
+			t.Errorf("%v: the Name should be loaded back from the plain shape not %v", apiName, plainConfiguration.Name())
+		}
+		if err := api.Save(plainConfiguration, testsPath+"save.json"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ = ioutil.ReadFile(testsPath + "save.json")
+		savedConfiguration, err := api.Load(testsPath + "save.json")
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: %v", apiName, err)
+
+		case !strings.Contains(string(content), "\"properties\""):
+			t.Errorf("%v: the default JSONCodec should save the envelope shape not\n%s", apiName, content)
+
+		case savedConfiguration.Name() != "nestedConfiguration" || savedConfiguration.Int("database.pool.size") != 10 || savedConfiguration.String("Key1") != "Value1":
+			t.Errorf("%v: the saved Configuration should be loaded back", apiName)
+		}
+	}
+}
+
+/*
+Try to Save a modified plain JSON file with a Preserve JSONCodec
+*/
+func TestPlainJSONPreserveConfiguration(t *testing.T) {
+
+	conf.RegisterCodec(".preserved", conf.JSONCodec{Preserve: true})
+	original, _ := ioutil.ReadFile(plainConfigurationFile)
+	fileName := testsPath + "plain.preserved"
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		ioutil.WriteFile(fileName, original, 0600)
+		configuration, err := api.Load(fileName)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		configuration = configuration.Add("database.pool.size", 20).Remove("hosts").Add("Key2", "Value2")
+		if err := api.Save(configuration, fileName); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(fileName)
+		expected := strings.Replace(string(original), "{\"size\": 10}", "{\"size\": 20}", 1)
+		expected = strings.Replace(expected, "  },\n  \"hosts\": [\"host1\", \"host2\"]\n", "  },\n  \"Key2\": \"Value2\"\n", 1)
+		if string(content) != expected {
+			t.Errorf("%v: content should be\n%s\nnot\n%s", apiName, expected, content)
+		}
+	}
+}
+
+/*
+Try to Load and Save the plain shape with a Plain JSONCodec given to LoadWith and SaveWith
+*/
+func TestPlainJSONLoadWithSaveWith(t *testing.T) {
+
+	fileName := testsPath + "plainWith.json"
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		// A plain object looking like the envelope shape
+		ioutil.WriteFile(fileName, []byte(`{"properties": {"Key1": {"value": "Value1"}}}`), 0600)
+		detected, err := api.Load(fileName)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		plain, err := api.LoadWith(fileName, conf.JSONCodec{Plain: true})
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		if err := api.SaveWith(plain.Add("Key2", "Value2"), fileName, conf.JSONCodec{Plain: true}); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(fileName)
+
+		switch {
+
+		case detected.String("Key1") != "Value1":
+			t.Errorf("%v: the envelope shape should be detected by Load", apiName)
+
+		case plain.String("properties.Key1.value") != "Value1" || plain.HasProperty("Key1"):
+			t.Errorf("%v: a Plain JSONCodec should always Load the plain shape", apiName)
+
+		case !strings.Contains(string(content), "\"Key2\": \"Value2\"") || !strings.Contains(string(content), "\"properties\": {"):
+			t.Errorf("%v: a Plain JSONCodec should Save the plain shape not\n%s", apiName, content)
+		}
+	}
+}
+
+/*
+Try to Parse envelope documents without properties, their "name" is the Name of an empty Configuration
+*/
+func TestPlainJSONEnvelopeWithoutProperties(t *testing.T) {
+
+	for apiName, api := range apis {
+		for _, content := range []string{`{"name": "x", "properties": null}`, `{"name": "x"}`, `{"name": "x", "properties": {}}`} {
+
+			configuration, err := api.Parse([]byte(content))
+			switch {
+
+			case err != nil:
+				t.Errorf("%v: %v should be parsed: %v", apiName, content, err)
+
+			case configuration.Name() != "x" || configuration.Size() != 0:
+				t.Errorf("%v: %v should be an empty Configuration named x not %v with %v", apiName, content, configuration.Name(), configuration.Keys())
+			}
+		}
+	}
+}
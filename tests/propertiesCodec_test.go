@@ -0,0 +1,99 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var (
+	validPropertiesConfigurationFile = testsPath + "validConfiguration.properties"
+)
+
+/*
+Try to Load a Configuration from a valid Java properties file
+*/
+func TestPropertiesLoadValidConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validPropertiesConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case !reflect.DeepEqual(configuration.Keys(), []string{"name", "Key1", "Key2", "Key3", "multi", "escaped key", "database", "cache", "cache.hosts", conf.RootPathKey}):
+			t.Errorf("%v: Keys should keep the file's order not %v", apiName, configuration.Keys())
+
+		case configuration.String("Key1") != "Value1" || configuration.String("Key2") != "Value2" || configuration.String("Key3") != "Value3":
+			t.Errorf("%v: '=', ':' and white spaces should separate keys and values", apiName)
+
+		case configuration.String("multi") != "first second" || configuration.String("escaped key") != "tab\there é":
+			t.Errorf("%v: continuation lines and escapes should be read not %q and %q", apiName, configuration.String("multi"), configuration.String("escaped key"))
+
+		case configuration.String("database.host") != "localhost" || configuration.Int("database.pool.size") != 10:
+			t.Errorf("%v: dotted keys should be loaded as child Configurations", apiName)
+
+		case configuration.String("cache") != "enabled" || !reflect.DeepEqual(configuration.StringSlice("cache.hosts"), []string{"host1", "host2"}):
+			t.Errorf("%v: cache and cache.hosts should both be kept as flat keys", apiName)
+		}
+	}
+}
+
+/*
+Try to Save a Configuration in the properties format and check the properties' order is preserved
+*/
+func TestPropertiesSaveAndLoad(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validPropertiesConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		configuration = configuration.Remove(conf.RootPathKey).Remove("Key2").Add("database.user", " admin=root\n")
+		if err := api.Save(configuration, testsPath+"save.properties"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(testsPath + "save.properties")
+		savedConfiguration, err := api.Load(testsPath + "save.properties")
+		os.Remove(testsPath + "save.properties")
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", apiName, err, content)
+		}
+
+		expectedContent := "name=legacy\nKey1=Value1\nKey3=Value3\nmulti=first second\nescaped\\ key=tab\\there \\u00E9\n" +
+			"database.host=localhost\ndatabase.port=5432\ndatabase.pool.size=10\ndatabase.user=\\ admin=root\\n\n" +
+			"cache=enabled\ncache.hosts=host1,host2\n"
+		switch {
+
+		case string(content) != expectedContent:
+			t.Errorf("%v: saved content should be\n%s\nnot\n%s", apiName, expectedContent, content)
+
+		case savedConfiguration.String("database.user") != " admin=root\n" || savedConfiguration.String("escaped key") != "tab\there é":
+			t.Errorf("%v: escaped values should be loaded back", apiName)
+		}
+	}
+}
+
+/*
+Try to Load invalid properties contents
+*/
+func TestPropertiesLoadInvalidConfiguration(t *testing.T) {
+
+	for _, content := range []string{"key = \\u00\n", "key = \\uZZZZ\n"} {
+
+		if err := ioutil.WriteFile(testsPath+"invalid.properties", []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conf.Default().Load(testsPath + "invalid.properties"); err == nil {
+			t.Errorf("Load() should return an error for %q", content)
+		}
+	}
+	os.Remove(testsPath + "invalid.properties")
+}
@@ -0,0 +1,170 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+/*
+Try to get the Source of loaded and added properties
+*/
+func TestPropertySource(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		for fileName, expectedSource := range map[string]conf.Source{
+			plainConfigurationFile:           {Kind: conf.SourceFile, Name: plainConfigurationFile, Line: 6, Column: 5},
+			validYAMLConfigurationFile:       {Kind: conf.SourceFile, Name: validYAMLConfigurationFile, Line: 6, Column: 3},
+			validINIConfigurationFile:        {Kind: conf.SourceFile, Name: validINIConfigurationFile, Line: 7, Column: 1},
+			validPropertiesConfigurationFile: {Kind: conf.SourceFile, Name: validPropertiesConfigurationFile, Line: 10, Column: 1},
+			nestedConfigurationFile:          {Kind: conf.SourceFile, Name: nestedConfigurationFile, Line: 13, Column: 11},
+		} {
+			configuration, err := api.Load(fileName)
+			if err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+			if source := configuration.Property("database.host").Source(); source != expectedSource {
+				t.Errorf("%v: database.host of %v should come from %v not %v", apiName, fileName, expectedSource, source)
+			}
+			if source := configuration.Property(conf.RootPathKey).Source(); source.Kind != conf.SourceFile || source.Name != fileName {
+				t.Errorf("%v: RootPath of %v should come from the file not %v", apiName, fileName, source)
+			}
+		}
+
+		configuration, _ := api.Load(plainConfigurationFile)
+		configuration = configuration.Add("database.host", "db.example.com")
+		if source := configuration.Property("database.host").Source(); source.String() != "program" || source.Kind != conf.SourceProgram {
+			t.Errorf("%v: an added Property should come from the program not %v", apiName, source)
+		}
+		if source := (conf.Source{Kind: conf.SourceFile, Name: "config.yaml", Line: 12, Column: 3}); source.String() != "file config.yaml:12:3" {
+			t.Errorf("%v: Source should be written \"file config.yaml:12:3\" not %q", apiName, source.String())
+		}
+	}
+}
+
+/*
+Try to Explain where a Value of a Layered Configuration comes from
+*/
+func TestExplainLayeredConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		file, err := api.Load(plainConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		defaults := api.New("defaults").Add("database.host", "localhost").Add("database.port", 5432)
+		overrides := api.New("overrides").Add("database.host", "db.example.com")
+		configuration := conf.Layered(defaults, file, overrides)
+
+		explanation := configuration.Explain("database.host")
+		switch {
+
+		case len(explanation) != 3:
+			t.Errorf("%v: database.host should be defined by 3 layers not %v", apiName, len(explanation))
+
+		case explanation[0].Value() != "db.example.com" || explanation[0].Source().Kind != conf.SourceProgram || explanation[2].Value() != "localhost":
+			t.Errorf("%v: the first Property should be the winning one and the last the default one", apiName)
+
+		case explanation[1].Source().Name != plainConfigurationFile || explanation[1].Source().Line != 6:
+			t.Errorf("%v: the second Property should come from the file not %v", apiName, explanation[1].Source())
+
+		case len(configuration.Explain("database.port")) != 1 || len(configuration.Explain("missing")) != 0:
+			t.Errorf("%v: only the layers defining the Property should be listed", apiName)
+		}
+	}
+}
+
+/*
+Try to get the positions and the order of the properties of a JSON file in the marshallableConfiguration format
+*/
+func TestPropertySourceMarshallableJSON(t *testing.T) {
+
+	fileName := testsPath + "ordered.json"
+	ioutil.WriteFile(fileName, []byte(`{
+  "name": "ordered",
+  "properties": {
+    "zeta": {"name": "zeta", "value": 1},
+    "alpha": {"name": "alpha", "value": {"name": "alpha", "properties": {"host": {"name": "host", "value": "db"}}}}
+  }
+}`), 0600)
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(fileName)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case !reflect.DeepEqual(configuration.Keys(), []string{"zeta", "alpha", conf.RootPathKey}):
+			t.Errorf("%v: the properties should keep the file's order not %v", apiName, configuration.Keys())
+
+		case configuration.Property("zeta").Source() != conf.Source{Kind: conf.SourceFile, Name: fileName, Line: 4, Column: 5}:
+			t.Errorf("%v: zeta should be located not %v", apiName, configuration.Property("zeta").Source())
+
+		case configuration.Property("alpha.host").Source().Line != 5 || configuration.String("alpha.host") != "db":
+			t.Errorf("%v: nested properties should be located not %v", apiName, configuration.Property("alpha.host").Source())
+		}
+	}
+}
+
+/*
+Try to get the Source of the child Configurations merged by a Layered Configuration
+*/
+func TestPropertySourceLayeredChild(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		file, err := api.Load(plainConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		defaults := api.New("defaults").Add("database.port", 5432)
+		configuration := conf.Layered(defaults, file)
+
+		switch {
+
+		case configuration.Property("database").Source() != file.Property("database").Source():
+			t.Errorf("%v: the merged child should come from the highest layer not %v", apiName, configuration.Property("database").Source())
+
+		case configuration.Sub("database").Property("port").Source().Kind != conf.SourceProgram:
+			t.Errorf("%v: the properties of the merged child should keep their Source", apiName)
+		}
+	}
+}
+
+/*
+Try to Explain a dotted name hidden or shadowed by another layer
+*/
+func TestExplainLayeredShadowedProperty(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		lower := api.New("lower").Add("database.host", "localhost")
+		literal, err := api.Parse([]byte(`{"database.host": "db.example.com"}`))
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		hidden := conf.Layered(lower, api.New("upper").Add("database", "disabled"))
+		shadowed := conf.Layered(literal, api.New("upper").Add("database.host", "db"))
+		explanation := shadowed.Explain("database.host")
+
+		switch {
+
+		case hidden.Property("database.host").Value() != nil || len(hidden.Explain("database.host")) != 0:
+			t.Errorf("%v: a Property hidden by a value should not be explained not %v", apiName, hidden.Explain("database.host"))
+
+		case shadowed.String("database.host") != "db.example.com" || len(explanation) != 2:
+			t.Errorf("%v: the literal name should win over the nested one of a higher layer", apiName)
+
+		case explanation[0].Value() != "db.example.com" || explanation[1].Value() != "db":
+			t.Errorf("%v: the first Property should be the winning one not %v", apiName, explanation[0].Value())
+		}
+	}
+}
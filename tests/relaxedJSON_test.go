@@ -0,0 +1,69 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	relaxedConfigurationFile = testsPath + "relaxedConfiguration.jsonc"
+)
+
+/*
+Try to Load a JSON Configuration with comments, trailing commas, unquoted keys and single quoted strings
+*/
+func TestRelaxedJSONLoadConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(relaxedConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.Name() != "relaxedConfiguration" || configuration.Size() != 4:
+			t.Errorf("%v: Configuration should be relaxedConfiguration with 4 properties not %v with %v", apiName, configuration.Name(), configuration.Size())
+
+		case configuration.String("Key1") != "it's \"quoted\" // not a comment":
+			t.Errorf("%v: comments markers in strings should be kept not %q", apiName, configuration.String("Key1"))
+
+		case configuration.String("url") != "http://example.com/*path*/":
+			t.Errorf("%v: url should be http://example.com/*path*/ not %q", apiName, configuration.String("url"))
+
+		case !reflect.DeepEqual(configuration.StringSlice("hosts"), []string{"host1", "host2"}):
+			t.Errorf("%v: hosts should be [host1 host2] not %v", apiName, configuration.StringSlice("hosts"))
+		}
+	}
+}
+
+/*
+Try to Load relaxed JSON with a strict JSONCodec, and invalid contents with both
+*/
+func TestStrictJSONLoadConfiguration(t *testing.T) {
+
+	conf.RegisterCodec(".strict", conf.JSONCodec{Strict: true})
+	content, _ := ioutil.ReadFile(relaxedConfigurationFile)
+	ioutil.WriteFile(testsPath+"relaxed.strict", content, 0600)
+	defer os.Remove(testsPath + "relaxed.strict")
+
+	if _, err := conf.Default().Load(testsPath + "relaxed.strict"); err == nil {
+		t.Errorf("A strict JSONCodec should reject comments")
+	} else if !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("The error should give the line of the syntax error not %v", err)
+	}
+
+	for _, content := range []string{"{\"name\": \"unterminated}", "{/* unterminated comment", "{\n\"name\": 'single\nline'}", "{\"name\": \"a\",,}"} {
+
+		ioutil.WriteFile(testsPath+"invalid.json", []byte(content), 0600)
+		if _, err := conf.Default().Load(testsPath + "invalid.json"); err == nil {
+			t.Errorf("Load() should return an error for %q", content)
+		}
+	}
+	os.Remove(testsPath + "invalid.json")
+}
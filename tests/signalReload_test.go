@@ -0,0 +1,72 @@
+package eliteConfiguration_test
+
+import (
+	"context"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+/*
+Try to reload a Configuration when the process receives SIGHUP
+*/
+func TestReloadOnSignal(t *testing.T) {
+
+	fileName := testsPath + "signaled.json"
+	ioutil.WriteFile(fileName, []byte(`{"host": "localhost", "port": 5432}`), 0600)
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		ioutil.WriteFile(fileName, []byte(`{"host": "localhost", "port": 5432}`), 0600)
+		published := make(chan conf.ChangeSet, 1)
+		ctx, cancel := context.WithCancel(context.Background())
+		live, reloadErrors, err := conf.ReloadOnSignal(ctx, api, fileName, conf.SignalOptions{
+			Validators:  []conf.Validator{portValidator},
+			Subscribers: []conf.ChangeCallback{func(configuration conf.Configuration, changes conf.ChangeSet) { published <- changes }},
+		})
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		process, _ := os.FindProcess(os.Getpid())
+
+		ioutil.WriteFile(fileName, []byte(`{"host": "db.example.com", "port": 5432}`), 0600)
+		process.Signal(syscall.SIGHUP)
+		select {
+		case changes := <-published:
+			if len(changes) != 1 || live.Get().String("host") != "db.example.com" {
+				t.Errorf("%v: the reloaded Configuration should be published not %v", apiName, changes)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%v: SIGHUP should reload the file", apiName)
+		}
+
+		ioutil.WriteFile(fileName, []byte(`{"host": "db", "port": -1}`), 0600)
+		process.Signal(syscall.SIGHUP)
+		select {
+		case err := <-reloadErrors:
+			if err == nil || live.Get().String("host") != "db.example.com" {
+				t.Errorf("%v: the invalid Configuration should be reported and not applied", apiName)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%v: the reload error should be reported", apiName)
+		}
+
+		cancel()
+		select {
+		case _, open := <-reloadErrors:
+			if open {
+				t.Errorf("%v: only one error should be reported", apiName)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%v: the report channel should be closed when the context is done", apiName)
+		}
+	}
+
+	if _, _, err := conf.ReloadOnSignal(context.Background(), conf.Default(), nonExistingConfigurationFile, conf.SignalOptions{}); err == nil {
+		t.Error("ReloadOnSignal() should return an error for a missing file")
+	}
+}
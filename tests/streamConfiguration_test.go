@@ -0,0 +1,111 @@
+package eliteConfiguration_test
+
+import (
+	"bytes"
+	"errors"
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/*
+failingWriter is an io.Writer always returning an error
+*/
+type failingWriter struct {
+}
+
+func (writer failingWriter) Write(content []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+/*
+Try to Marshal a Configuration and Parse it back
+*/
+func TestMarshalAndParseConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		content, err := api.Marshal(api.New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1"))
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		configuration, err := api.Parse(content)
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: %v", apiName, err)
+
+		case configuration.Name() != "nestedConfiguration" || configuration.Size() != 2 || configuration.HasProperty(conf.RootPathKey):
+			t.Errorf("%v: Parse should return the Configuration without RootPath not %v", apiName, configuration.Keys())
+
+		case configuration.Int("database.pool.size") != 10:
+			t.Errorf("%v: database.pool.size should be 10 not %v", apiName, configuration.Int("database.pool.size"))
+		}
+
+		if _, err := api.Parse([]byte("{\"name\": ")); err == nil {
+			t.Errorf("%v: Parse should return an error for invalid JSON", apiName)
+		}
+	}
+}
+
+/*
+Try to Save a Configuration to an io.Writer and Load it back from an io.Reader
+*/
+func TestSaveToAndLoadFromConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		nestedConfiguration := api.New("nestedConfiguration").Add("database.host", "localhost").Add("database.pool.size", 10.0).Add("Key1", "Value1")
+		var buffer bytes.Buffer
+		if err := api.SaveTo(nestedConfiguration, &buffer); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		configuration, err := api.LoadFrom(strings.NewReader(buffer.String()))
+
+		switch {
+
+		case err != nil:
+			t.Errorf("%v: %v", apiName, err)
+
+		case configuration.String("Key1") != "Value1" || configuration.String("database.host") != "localhost":
+			t.Errorf("%v: the Configuration should be loaded back from the reader", apiName)
+		}
+
+		if err := api.SaveTo(nestedConfiguration, failingWriter{}); err == nil {
+			t.Errorf("%v: SaveTo should return the writer's error", apiName)
+		}
+	}
+}
+
+/*
+Try to Load a file and LoadFrom the same content, both should decode the same Configuration with the same positions
+*/
+func TestLoadFromSameAsLoad(t *testing.T) {
+
+	for apiName, api := range apis {
+		for _, fileName := range []string{nestedConfigurationFile, plainConfigurationFile} {
+
+			loaded, err := api.Load(fileName)
+			if err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+			content, _ := ioutil.ReadFile(fileName)
+			read, err := api.LoadFrom(bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+
+			switch {
+
+			case read.Name() != loaded.Name() || !reflect.DeepEqual(read.Keys(), loaded.Remove(conf.RootPathKey).Keys()):
+				t.Errorf("%v: %v should be read as %v %v not %v %v", apiName, fileName, loaded.Name(), loaded.Keys(), read.Name(), read.Keys())
+
+			case read.Property("database").Source().Line != loaded.Property("database").Source().Line || read.Property("database").Source().Line == 0:
+				t.Errorf("%v: %v properties should be located like the loaded ones", apiName, fileName)
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	bindingConfigurationFile = testsPath + "bindingConfiguration.json"
+)
+
+type databaseSettings struct {
+	Host    string `conf:"host,required"`
+	Port    int    `conf:"port,default=3306"`
+	Retries uint   `conf:"retries,default=3"`
+}
+
+type bindingSettings struct {
+	Database databaseSettings `conf:"database"`
+	Hosts    []string         `conf:"hosts"`
+	Limits   map[string]int   `conf:"limits"`
+	Timeout  time.Duration    `conf:"timeout"`
+	Tags     []string         `conf:"tags,default=a,b,required"`
+	Debug    *bool            `conf:"debug,default=true"`
+	Ignored  string           `conf:"-"`
+}
+
+/*
+Try to Bind a loaded Configuration into a tagged struct
+*/
+func TestBindLoadedConfiguration(t *testing.T) {
+
+	configuration, err := conf.Default().Load(bindingConfigurationFile)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	settings := bindingSettings{Ignored: "Ignored"}
+	switch err := conf.Bind(configuration, &settings); {
+
+	case err != nil:
+		t.Error(err.Error())
+
+	case settings.Database.Host != "localhost" || settings.Database.Port != 5432 || settings.Database.Retries != 3:
+		t.Errorf("Database should be {localhost 5432 3} not %v", settings.Database)
+
+	case len(settings.Hosts) != 2 || settings.Hosts[1] != "host2":
+		t.Errorf("Hosts should be [host1 host2] not %v", settings.Hosts)
+
+	case settings.Limits["read"] != 10 || settings.Limits["write"] != 5:
+		t.Errorf("Limits should be map[read:10 write:5] not %v", settings.Limits)
+
+	case settings.Timeout != 5*time.Second:
+		t.Errorf("Timeout should be 5s not %v", settings.Timeout)
+
+	case len(settings.Tags) != 2 || settings.Tags[0] != "a" || settings.Tags[1] != "b":
+		t.Errorf("Tags should be [a b] not %v", settings.Tags)
+
+	case settings.Debug == nil || !*settings.Debug:
+		t.Error("Debug should be true")
+
+	case settings.Ignored != "Ignored":
+		t.Errorf("Ignored should not be changed, not %v", settings.Ignored)
+	}
+}
+
+/*
+Check that Bind report all the missing and mistyped fields in one error
+*/
+func TestBindAggregatedErrors(t *testing.T) {
+
+	configuration := conf.Default().New("invalid").Add("database", map[string]interface{}{"port": "NotAPort"}).Add("timeout", true)
+
+	var settings bindingSettings
+	err := conf.Bind(configuration, &settings)
+	if err == nil {
+		t.Fatal("Bind() should return an error")
+	}
+
+	for _, expected := range []string{"database.host", "database.port", "timeout"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Bind() error should report \"%v\": %v", expected, err.Error())
+		}
+	}
+}
+
+/*
+Check that Bind refuse a target which is not a pointer to a struct
+*/
+func TestBindInvalidTarget(t *testing.T) {
+
+	if err := conf.Bind(validImmutableConfiguration, bindingSettings{}); err == nil {
+		t.Error("Bind() should return an error for a non pointer target")
+	}
+}
+
+/*
+Try to create a Configuration from a tagged struct, Save it and Bind it back
+*/
+func TestFromStructSaveAndBind(t *testing.T) {
+
+	debug := false
+	source := bindingSettings{
+		Database: databaseSettings{Host: "db.local"},
+		Hosts:    []string{"host1"},
+		Limits:   map[string]int{"read": 1},
+		Timeout:  time.Minute,
+		Debug:    &debug,
+		Ignored:  "Ignored",
+	}
+
+	for apiName, api := range apis {
+
+		configuration, err := conf.FromStruct(api, &source)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		if configuration.HasProperty("Ignored") {
+			t.Errorf("%v: FromStruct() should skip fields tagged with \"-\"", apiName)
+		}
+
+		if err := api.Save(configuration, testsPath+"fromStruct.json"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		loadedConfiguration, err := api.Load(testsPath + "fromStruct.json")
+		os.Remove(testsPath + "fromStruct.json")
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		var settings bindingSettings
+		switch err := conf.Bind(loadedConfiguration, &settings); {
+
+		case err != nil:
+			t.Errorf("%v: %v", apiName, err)
+
+		case settings.Database.Host != "db.local" || settings.Database.Port != 3306:
+			t.Errorf("%v: Database should be {db.local 3306 3} not %v", apiName, settings.Database)
+
+		case settings.Timeout != time.Minute:
+			t.Errorf("%v: Timeout should be 1m0s not %v", apiName, settings.Timeout)
+
+		case len(settings.Tags) != 2:
+			t.Errorf("%v: Tags should take their default value [a b] not %v", apiName, settings.Tags)
+
+		case settings.Debug == nil || *settings.Debug:
+			t.Errorf("%v: Debug should be false", apiName)
+		}
+	}
+}
+
+/*
+Check that FromStruct refuse a source which is not a struct
+*/
+func TestFromStructInvalidSource(t *testing.T) {
+
+	if _, err := conf.FromStruct(conf.Default(), "NotAStruct"); err == nil {
+		t.Error("FromStruct() should return an error for a non struct source")
+	}
+}
@@ -0,0 +1,159 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	validTOMLConfigurationFile = testsPath + "validConfiguration.toml"
+)
+
+/*
+Try to Load a Configuration from a valid TOML file
+*/
+func TestTOMLLoadValidConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validTOMLConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.Size() != 13:
+			t.Errorf("%v: Configuration's size should be 13 not %v", apiName, configuration.Size())
+
+		case configuration.String("Key1") != "Value1" || configuration.String("Key2") != "Value2" || configuration.String("Key 3") != "Multi-line\nvalue":
+			t.Errorf("%v: Key1, Key2 and \"Key 3\" should be read as strings", apiName)
+
+		case !reflect.DeepEqual(configuration.StringSlice("hosts"), []string{"host1", "host2"}):
+			t.Errorf("%v: hosts should be [host1 host2] not %v", apiName, configuration.StringSlice("hosts"))
+
+		case configuration.Int("port") != 8080 || configuration.Float("ratio") != 150 || !configuration.Bool("enabled"):
+			t.Errorf("%v: port, ratio and enabled should be 8080, 150 and true", apiName)
+
+		case configuration.String("created") != "1979-05-27 07:32:00Z":
+			t.Errorf("%v: created should be read as a string not %v", apiName, returnValue(configuration.Value("created"))[0])
+
+		case configuration.Int("point.y") != 2 || configuration.String("site.name") != "example":
+			t.Errorf("%v: inline tables and dotted keys should be loaded as child Configurations", apiName)
+
+		case configuration.String("database.host") != "localhost" || configuration.Int("database.pool.size") != 10:
+			t.Errorf("%v: tables should be loaded as child Configurations", apiName)
+		}
+
+		servers, _ := configuration.Value("servers")
+		if items, ok := servers.([]interface{}); !ok || len(items) != 2 || items[1].(map[string]interface{})["name"] != "server2" {
+			t.Errorf("%v: servers should be an array of 2 tables not %v", apiName, servers)
+		}
+	}
+}
+
+/*
+Try to Save a Configuration in TOML and Load it back
+*/
+func TestTOMLSaveAndLoad(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validTOMLConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		configuration = configuration.Add("quoted", "\"quoted\"\t\\")
+		if err := api.Save(configuration, testsPath+"save.toml"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		savedConfiguration, err := api.Load(testsPath + "save.toml")
+		content, _ := ioutil.ReadFile(testsPath + "save.toml")
+		os.Remove(testsPath + "save.toml")
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", apiName, err, content)
+		}
+
+		for _, key := range []string{"Key1", "Key 3", "hosts", "port", "ratio", "enabled", "created", "servers", "quoted", "database.pool.size"} {
+			if !reflect.DeepEqual(returnValue(configuration.Value(key))[0], returnValue(savedConfiguration.Value(key))[0]) {
+				t.Errorf("%v: %v should be %#v not %#v\n%s", apiName, key, returnValue(configuration.Value(key))[0], returnValue(savedConfiguration.Value(key))[0], content)
+			}
+		}
+	}
+}
+
+/*
+Try to Load invalid TOML contents
+*/
+func TestTOMLLoadInvalidConfiguration(t *testing.T) {
+
+	for _, content := range []string{"key = \n", "key = 1\nkey = 2\n", "[table]\n[table]\n", "key = \"unterminated\n", "key = 1 2\n", "point = {x = 1}\n[point]\n"} {
+
+		if err := ioutil.WriteFile(testsPath+"invalid.toml", []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conf.Default().Load(testsPath + "invalid.toml"); err == nil {
+			t.Errorf("Load() should return an error for %q", content)
+		}
+	}
+	os.Remove(testsPath + "invalid.toml")
+}
+
+/*
+Try to Save a Configuration with a nil Value in TOML
+*/
+func TestTOMLSaveNilValue(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("nil").Add("Key1", "Value1").Add("database.password", nil)
+		err := api.Save(configuration, testsPath+"nil.toml")
+		os.Remove(testsPath + "nil.toml")
+
+		if err == nil || !strings.Contains(err.Error(), "database.password") {
+			t.Errorf("%v: a nil Value should be an error naming its key not %v", apiName, err)
+		}
+	}
+}
+
+/*
+Try to Save floats and date-times in TOML, they should keep their TOML type when loaded back
+*/
+func TestTOMLSaveKeepsTypes(t *testing.T) {
+
+	fileName := testsPath + "types.toml"
+	defer os.Remove(fileName)
+
+	for apiName, api := range apis {
+
+		configuration := api.New("types").Add("ratio", 2.0).Add("large", 1e21).Add("port", 8080).
+			Add("created", "1979-05-27T07:32:00Z").Add("updated", time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC))
+		if err := api.Save(configuration, fileName); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		content, _ := ioutil.ReadFile(fileName)
+		savedConfiguration, err := api.Load(fileName)
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", apiName, err, content)
+		}
+
+		expectedContent := "ratio = 2.0\nlarge = 1e+21\nport = 8080\ncreated = 1979-05-27T07:32:00Z\nupdated = 1979-05-27T07:32:00Z\n"
+		switch {
+
+		case !strings.HasPrefix(string(content), expectedContent):
+			t.Errorf("%v: saved content should start with\n%s\nnot\n%s", apiName, expectedContent, content)
+
+		case !reflect.DeepEqual(returnValue(savedConfiguration.Value("ratio"))[0], 2.0) || !reflect.DeepEqual(returnValue(savedConfiguration.Value("large"))[0], 1e21):
+			t.Errorf("%v: floats should be loaded back as floats not %#v", apiName, returnValue(savedConfiguration.Value("ratio"))[0])
+
+		case !reflect.DeepEqual(returnValue(savedConfiguration.Value("port"))[0], 8080) || savedConfiguration.String("created") != "1979-05-27T07:32:00Z":
+			t.Errorf("%v: integers and date-times should be loaded back unchanged\n%s", apiName, content)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"testing"
+	"time"
+)
+
+var (
+	typedConfigurationFile = testsPath + "typedConfiguration.json"
+	apis                   = map[string]conf.API{"Immutable": conf.Immutable(), "Mutable": conf.Mutable(), "Concurrent": conf.Concurrent()}
+)
+
+/*
+Try to get typed Values from a loaded JSON Configuration
+*/
+func TestTypedValuesFromLoadedConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(typedConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.String("String") != "Value":
+			t.Errorf("%v: String(\"String\") should be \"Value\" not \"%v\"", apiName, configuration.String("String"))
+
+		case configuration.Int("Int") != 8080:
+			t.Errorf("%v: Int(\"Int\") should be 8080 not %v", apiName, configuration.Int("Int"))
+
+		case configuration.Bool("Bool") != true:
+			t.Errorf("%v: Bool(\"Bool\") should be true", apiName)
+
+		case configuration.Float("Float") != 1.5:
+			t.Errorf("%v: Float(\"Float\") should be 1.5 not %v", apiName, configuration.Float("Float"))
+
+		case configuration.Duration("Duration") != 5*time.Second:
+			t.Errorf("%v: Duration(\"Duration\") should be 5s not %v", apiName, configuration.Duration("Duration"))
+
+		case len(configuration.StringSlice("StringSlice")) != 2 || configuration.StringSlice("StringSlice")[1] != "Value2":
+			t.Errorf("%v: StringSlice(\"StringSlice\") should be [Value1 Value2] not %v", apiName, configuration.StringSlice("StringSlice"))
+		}
+	}
+}
+
+/*
+Check the coercion of compatible raw Values
+*/
+func TestTypedValuesCoercion(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("coercion").Add("IntString", " 42 ").Add("IntFloat", 42.0).Add("BoolString", "true").Add("FloatInt", 3).Add("DurationInt", 1000).Add("StringSliceString", "a, b,c").
+			Add("StringFloat", 5432.0).Add("StringDecimal", 0.5).Add("StringInt", 8080).Add("StringBool", true)
+
+		switch {
+
+		case configuration.Int("IntString") != 42:
+			t.Errorf("%v: Int(\"IntString\") should be 42 not %v", apiName, configuration.Int("IntString"))
+
+		case configuration.Int("IntFloat") != 42:
+			t.Errorf("%v: Int(\"IntFloat\") should be 42 not %v", apiName, configuration.Int("IntFloat"))
+
+		case !configuration.Bool("BoolString"):
+			t.Errorf("%v: Bool(\"BoolString\") should be true", apiName)
+
+		case configuration.Float("FloatInt") != 3:
+			t.Errorf("%v: Float(\"FloatInt\") should be 3 not %v", apiName, configuration.Float("FloatInt"))
+
+		case configuration.Duration("DurationInt") != time.Microsecond:
+			t.Errorf("%v: Duration(\"DurationInt\") should be 1µs not %v", apiName, configuration.Duration("DurationInt"))
+
+		case len(configuration.StringSlice("StringSliceString")) != 3 || configuration.StringSlice("StringSliceString")[2] != "c":
+			t.Errorf("%v: StringSlice(\"StringSliceString\") should be [a b c] not %v", apiName, configuration.StringSlice("StringSliceString"))
+
+		case configuration.String("StringFloat") != "5432" || configuration.String("StringDecimal") != "0.5":
+			t.Errorf("%v: String(\"StringFloat\") should be 5432 not %v", apiName, configuration.String("StringFloat"))
+
+		case configuration.String("StringInt") != "8080" || configuration.String("StringBool") != "true":
+			t.Errorf("%v: String() should format integers and booleans", apiName)
+		}
+	}
+}
+
+/*
+Check that typed Values return an error when the raw Value can't be converted
+*/
+func TestTypedValuesMismatch(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("mismatch").Add("Float", 1.5).Add("String", "Value").Add("Array", []interface{}{"a", 1.0})
+
+		if _, err := configuration.IntValue("Float"); err == nil {
+			t.Errorf("%v: IntValue(\"Float\") should return an error for a non integral float", apiName)
+		}
+
+		if _, err := configuration.BoolValue("String"); err == nil {
+			t.Errorf("%v: BoolValue(\"String\") should return an error", apiName)
+		}
+
+		if _, err := configuration.StringValue("Array"); err == nil {
+			t.Errorf("%v: StringValue(\"Array\") should return an error", apiName)
+		}
+
+		if _, err := configuration.StringSliceValue("Array"); err == nil {
+			t.Errorf("%v: StringSliceValue(\"Array\") should return an error for an array with non string items", apiName)
+		}
+
+		if _, err := configuration.DurationValue("NonExistingKey"); err == nil {
+			t.Errorf("%v: DurationValue(\"NonExistingKey\") should return an error", apiName)
+		}
+	}
+}
+
+/*
+Check the default and zero values returned by typed Values
+*/
+func TestTypedValuesWithDefault(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration := api.New("default").Add("Float", 1.5).Add("Int", 1.0)
+
+		switch {
+
+		case configuration.IntWithDefault("Float", 7) != 7:
+			t.Errorf("%v: IntWithDefault(\"Float\", 7) should be 7 not %v", apiName, configuration.IntWithDefault("Float", 7))
+
+		case configuration.IntWithDefault("Int", 7) != 1:
+			t.Errorf("%v: IntWithDefault(\"Int\", 7) should be 1 not %v", apiName, configuration.IntWithDefault("Int", 7))
+
+		case configuration.StringWithDefault("NonExistingKey", "Default") != "Default":
+			t.Errorf("%v: StringWithDefault(\"NonExistingKey\", \"Default\") should be \"Default\"", apiName)
+
+		case configuration.Int("NonExistingKey") != 0:
+			t.Errorf("%v: Int(\"NonExistingKey\") should be 0 not %v", apiName, configuration.Int("NonExistingKey"))
+		}
+	}
+}
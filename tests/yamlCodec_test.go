@@ -0,0 +1,182 @@
+package eliteConfiguration_test
+
+import (
+	conf "github.com/EliteSystems/eliteConfiguration"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	validYAMLConfigurationFile = testsPath + "validConfiguration.yaml"
+)
+
+/*
+Try to Load a Configuration from a valid YAML file
+*/
+func TestYAMLLoadValidConfiguration(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validYAMLConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		switch {
+
+		case configuration.Size() != 12:
+			t.Errorf("%v: Configuration's size should be 12 not %v", apiName, configuration.Size())
+
+		case configuration.String("Key1") != "Value1" || configuration.String("Key2") != "Value2" || configuration.String("Key3") != "Value3":
+			t.Errorf("%v: Key1, Key2 and Key3 should be Value1, Value2 and Value3", apiName)
+
+		case configuration.Int("database.port") != 5432 || configuration.Duration("database.pool.timeout").String() != "5s":
+			t.Errorf("%v: nested mappings should be loaded as child Configurations", apiName)
+
+		case !reflect.DeepEqual(configuration.StringSlice("hosts"), []string{"host1", "host2"}):
+			t.Errorf("%v: hosts should be [host1 host2] not %v", apiName, configuration.StringSlice("hosts"))
+
+		case configuration.String("description") != "First line\nSecond line\n":
+			t.Errorf("%v: description should be a literal block scalar not %q", apiName, configuration.String("description"))
+
+		case configuration.String("folded") != "Folded text":
+			t.Errorf("%v: folded should be a folded block scalar not %q", apiName, configuration.String("folded"))
+
+		case !configuration.HasProperty("empty") || configuration.ValueWithDefault("nothing", "Default") != nil:
+			t.Errorf("%v: empty values should be loaded as null", apiName)
+		}
+
+		servers, _ := configuration.Value("servers")
+		if items, ok := servers.([]interface{}); !ok || len(items) != 2 || items[1].(map[string]interface{})["enabled"] != false {
+			t.Errorf("%v: servers should be a sequence of 2 mappings not %v", apiName, servers)
+		}
+
+		flags, _ := configuration.Value("flags")
+		if items, ok := flags.([]interface{}); !ok || len(items) != 3 || items[2].(map[string]interface{})["c"] != 1 {
+			t.Errorf("%v: flags should be a flow sequence of 3 items not %v", apiName, flags)
+		}
+	}
+}
+
+/*
+Try to Save a Configuration in YAML and Load it back
+*/
+func TestYAMLSaveAndLoad(t *testing.T) {
+
+	for apiName, api := range apis {
+
+		configuration, err := api.Load(validYAMLConfigurationFile)
+		if err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+
+		if err := api.Save(configuration.Add("quoted", "true: #1\n"), testsPath+"save.yaml"); err != nil {
+			t.Fatalf("%v: %v", apiName, err)
+		}
+		savedConfiguration, err := api.Load(testsPath + "save.yaml")
+		content, _ := ioutil.ReadFile(testsPath + "save.yaml")
+		os.Remove(testsPath + "save.yaml")
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", apiName, err, content)
+		}
+
+		for _, key := range configuration.Keys() {
+			if key != conf.RootPathKey && key != "database" && !reflect.DeepEqual(returnValue(configuration.Value(key))[0], returnValue(savedConfiguration.Value(key))[0]) {
+				t.Errorf("%v: %v should be %#v not %#v\n%s", apiName, key, returnValue(configuration.Value(key))[0], returnValue(savedConfiguration.Value(key))[0], content)
+			}
+		}
+		if savedConfiguration.Int("database.pool.size") != 10 {
+			t.Errorf("%v: database.pool.size should be 10 not %v\n%s", apiName, savedConfiguration.Int("database.pool.size"), content)
+		}
+	}
+}
+
+/*
+Try to Load invalid YAML contents
+*/
+func TestYAMLLoadInvalidConfiguration(t *testing.T) {
+
+	for _, content := range []string{"- not\n- a mapping\n", "key: value\n  bad: indentation\n", "key: &anchor value\n", "key: [unterminated\n", "key: 1\nkey: 2\n",
+		"key:\n\tsub: value\n", "key:\n  sub: value\n  \tother: value\n", "list:\n  - a\n\t- b\n"} {
+
+		if err := ioutil.WriteFile(testsPath+"invalid.yaml", []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conf.Default().Load(testsPath + "invalid.yaml"); err == nil {
+			t.Errorf("Load() should return an error for %q", content)
+		}
+	}
+	os.Remove(testsPath + "invalid.yaml")
+}
+
+/*
+Try to Load YAML contents indented with tabs
+*/
+func TestYAMLLoadTabIndentation(t *testing.T) {
+
+	fileName := testsPath + "tabs.yaml"
+	defer os.Remove(fileName)
+
+	if err := ioutil.WriteFile(fileName, []byte("database:\n  host: localhost\n\tport: 5432\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conf.Default().Load(fileName); err == nil || !strings.Contains(err.Error(), "line 3: tabs are not allowed") {
+		t.Errorf("Load() should locate the tab indentation not %v", err)
+	}
+
+	if err := ioutil.WriteFile(fileName, []byte("script: |\n  run\n  \tindented\n\t# comment\nkey: \"a\\tb\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	configuration, err := conf.Default().Load(fileName)
+	switch {
+
+	case err != nil:
+		t.Errorf("tabs in contents and before comments should be allowed: %v", err)
+
+	case configuration.String("script") != "run\n\tindented\n" || configuration.String("key") != "a\tb":
+		t.Errorf("tabs should be kept in the values not %q %q", configuration.String("script"), configuration.String("key"))
+	}
+}
+
+/*
+Try to Save integral floats and integers in YAML and JSON, the YAML numbers should keep their type and the typed
+accessors should return the same values for both formats
+*/
+func TestYAMLSaveNumbers(t *testing.T) {
+
+	defer os.Remove(testsPath + "numbers.yaml")
+	defer os.Remove(testsPath + "numbers.json")
+
+	for apiName, api := range apis {
+
+		configuration := api.New("numbers").Add("ratio", 2.0).Add("large", 1e21).Add("port", 8080)
+		loaded := map[string]conf.Configuration{}
+		for _, extension := range []string{".yaml", ".json"} {
+			if err := api.Save(configuration, testsPath+"numbers"+extension); err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+			savedConfiguration, err := api.Load(testsPath + "numbers" + extension)
+			if err != nil {
+				t.Fatalf("%v: %v", apiName, err)
+			}
+			loaded[extension] = savedConfiguration
+		}
+		content, _ := ioutil.ReadFile(testsPath + "numbers.yaml")
+
+		switch {
+
+		case !strings.HasPrefix(string(content), "ratio: 2.0\nlarge: 1e+21\nport: 8080\n"):
+			t.Errorf("%v: integral floats should keep a decimal point not\n%s", apiName, content)
+
+		case !reflect.DeepEqual(returnValue(loaded[".yaml"].Value("ratio"))[0], 2.0) || !reflect.DeepEqual(returnValue(loaded[".yaml"].Value("port"))[0], 8080):
+			t.Errorf("%v: YAML floats and integers should be loaded back with their type\n%s", apiName, content)
+
+		case loaded[".yaml"].Int("ratio") != loaded[".json"].Int("ratio") || loaded[".yaml"].Float("port") != loaded[".json"].Float("port") ||
+			loaded[".yaml"].Int("port") != 8080 || loaded[".json"].Float("ratio") != 2:
+			t.Errorf("%v: Int and Float should return the same values for YAML and JSON", apiName)
+		}
+	}
+}
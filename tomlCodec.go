@@ -0,0 +1,786 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+TOMLCodec reads and writes TOML files: the root table holds the properties and nested tables become
+child Configurations. Date-times are read as strings, and the strings holding a TOML date-time are written back as
+date-times (like the time.Time values) so they keep their TOML type. Floats are always written with a decimal point or
+an exponent, null values can't be written and the Configuration's Name is not stored
+*/
+type TOMLCodec struct {
+}
+
+var (
+	tomlBareKeyPattern  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	tomlIntegerPattern  = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	tomlFloatPattern    = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)((\.[0-9](_?[0-9])*)([eE][+-]?[0-9](_?[0-9])*)?|[eE][+-]?[0-9](_?[0-9])*)$`)
+	tomlDateTimePattern = regexp.MustCompile(`^([0-9]{4}-[0-9]{2}-[0-9]{2}([Tt ][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})?)?|[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?)$`)
+	tomlPrefixedPattern = map[string]*regexp.Regexp{
+		"0x": regexp.MustCompile(`^0x[0-9A-Fa-f](_?[0-9A-Fa-f])*$`),
+		"0o": regexp.MustCompile(`^0o[0-7](_?[0-7])*$`),
+		"0b": regexp.MustCompile(`^0b[01](_?[01])*$`),
+	}
+)
+
+/*
+tomlParser is the internal state used to decode a TOML document
+*/
+type tomlParser struct {
+	content  string
+	position int
+	root     *orderedObject
+	current  *orderedObject
+	defined  map[*orderedObject]bool
+	inlines  map[*orderedObject]bool
+}
+
+/*
+Decode the TOML content into the configuration
+*/
+func (codec TOMLCodec) Decode(content []byte, configuration Configuration) (Configuration, error) {
+
+	parser := &tomlParser{content: string(content), root: newOrderedObject(), defined: make(map[*orderedObject]bool), inlines: make(map[*orderedObject]bool)}
+	parser.current = parser.root
+	if err := parser.parse(); err != nil {
+		return nil, err
+	}
+	return fromObject(parser.root, configuration), nil
+}
+
+/*
+newError return a new configurationError located at the parser's position
+*/
+func (parser *tomlParser) newError(message string) error {
+
+	line := strings.Count(parser.content[:parser.position], "\n") + 1
+	return newError("eliteConfiguration.TOMLCodec.Decode()", fmt.Errorf("line %v: %v", line, message))
+}
+
+/*
+peek return the character at the position or 0 at the end of the content
+*/
+func (parser *tomlParser) peek() byte {
+
+	if parser.position < len(parser.content) {
+		return parser.content[parser.position]
+	}
+	return 0
+}
+
+/*
+hasPrefix check if the content at the position starts with prefix
+*/
+func (parser *tomlParser) hasPrefix(prefix string) bool {
+
+	return strings.HasPrefix(parser.content[parser.position:], prefix)
+}
+
+/*
+skipSpaces move the position after the spaces and tabs
+*/
+func (parser *tomlParser) skipSpaces() {
+
+	for parser.peek() == ' ' || parser.peek() == '\t' {
+		parser.position++
+	}
+}
+
+/*
+skipBlank move the position after the spaces, new lines and comments
+*/
+func (parser *tomlParser) skipBlank() {
+
+	for {
+		parser.skipSpaces()
+		switch {
+		case parser.peek() == '#':
+			for parser.position < len(parser.content) && parser.peek() != '\n' {
+				parser.position++
+			}
+		case parser.peek() == '\n':
+			parser.position++
+		case parser.hasPrefix("\r\n"):
+			parser.position += 2
+		default:
+			return
+		}
+	}
+}
+
+/*
+parseEndOfLine check that only spaces and a comment remain on the line
+*/
+func (parser *tomlParser) parseEndOfLine() error {
+
+	parser.skipSpaces()
+	if parser.peek() == '#' {
+		for parser.position < len(parser.content) && parser.peek() != '\n' {
+			parser.position++
+		}
+	}
+	switch {
+	case parser.position == len(parser.content):
+		return nil
+	case parser.peek() == '\n':
+		parser.position++
+		return nil
+	case parser.hasPrefix("\r\n"):
+		parser.position += 2
+		return nil
+	}
+	return parser.newError(fmt.Sprintf("unexpected character '%c', a new line was expected", parser.peek()))
+}
+
+/*
+parse read all the document's tables and key/value pairs
+*/
+func (parser *tomlParser) parse() error {
+
+	for {
+		parser.skipBlank()
+		if parser.position == len(parser.content) {
+			return nil
+		}
+
+		var err error
+		if parser.peek() == '[' {
+			err = parser.parseTableHeader()
+		} else {
+			err = parser.parseKeyValue(parser.current)
+		}
+		if err == nil {
+			err = parser.parseEndOfLine()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+/*
+parseTableHeader read a [table] or a [[array.of.tables]] header and make it the current table
+*/
+func (parser *tomlParser) parseTableHeader() error {
+
+	arrayOfTables := parser.hasPrefix("[[")
+	if arrayOfTables {
+		parser.position += 2
+	} else {
+		parser.position++
+	}
+
+	parser.skipSpaces()
+	keys, err := parser.parseKey()
+	if err != nil {
+		return err
+	}
+	parser.skipSpaces()
+	if arrayOfTables && !parser.hasPrefix("]]") || !arrayOfTables && parser.peek() != ']' {
+		return parser.newError("unterminated table header")
+	}
+	if arrayOfTables {
+		parser.position += 2
+	} else {
+		parser.position++
+	}
+
+	table, err := parser.walkTables(parser.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+
+	key := keys[len(keys)-1]
+	existing, exist := table.values[key]
+	if arrayOfTables {
+		// Only an array created by [[...]] headers can be extended
+		items, isArray := existing.([]interface{})
+		if exist && (!isArray || len(items) == 0 || lastTable(items) == nil || parser.inlines[lastTable(items)]) {
+			return parser.newError("key \"" + key + "\" is already defined")
+		}
+		parser.current = newOrderedObject()
+		parser.defined[parser.current] = true
+		table.set(key, append(items, parser.current))
+		return nil
+	}
+
+	child, isTable := existing.(*orderedObject)
+	switch {
+	case !exist:
+		child = newOrderedObject()
+		table.set(key, child)
+	case !isTable || parser.defined[child] || parser.inlines[child]:
+		return parser.newError("table \"" + strings.Join(keys, ".") + "\" is already defined")
+	}
+	parser.defined[child] = true
+	parser.current = child
+	return nil
+}
+
+/*
+lastTable return the last table of an array of tables
+*/
+func lastTable(items []interface{}) *orderedObject {
+
+	table, _ := items[len(items)-1].(*orderedObject)
+	return table
+}
+
+/*
+walkTables return the table designated by keys from table, creating the missing ones.
+The last table of an array of tables is used
+*/
+func (parser *tomlParser) walkTables(table *orderedObject, keys []string) (*orderedObject, error) {
+
+	for _, key := range keys {
+		switch existing := table.values[key].(type) {
+		case nil:
+			child := newOrderedObject()
+			table.set(key, child)
+			table = child
+		case *orderedObject:
+			if parser.inlines[existing] {
+				return nil, parser.newError("inline table \"" + key + "\" can't be extended")
+			}
+			table = existing
+		case []interface{}:
+			if len(existing) > 0 && lastTable(existing) != nil && !parser.inlines[lastTable(existing)] {
+				table = lastTable(existing)
+				continue
+			}
+			return nil, parser.newError("key \"" + key + "\" is not a table")
+		default:
+			return nil, parser.newError("key \"" + key + "\" is not a table")
+		}
+	}
+	return table, nil
+}
+
+/*
+parseKeyValue read a key = value pair into the table
+*/
+func (parser *tomlParser) parseKeyValue(table *orderedObject) error {
+
+	start := parser.position
+	keys, err := parser.parseKey()
+	if err != nil {
+		return err
+	}
+	parser.skipSpaces()
+	if parser.peek() != '=' {
+		return parser.newError("'=' was expected after the key")
+	}
+	parser.position++
+	parser.skipSpaces()
+
+	value, err := parser.parseValue()
+	if err != nil {
+		return err
+	}
+
+	// Dotted keys define tables which can't be redefined by a header
+	target := table
+	for _, key := range keys[:len(keys)-1] {
+		child, isTable := target.values[key].(*orderedObject)
+		if _, exist := target.values[key]; !exist {
+			child, isTable = newOrderedObject(), true
+			target.set(key, child)
+			parser.defined[child] = true
+		}
+		if !isTable || parser.inlines[child] {
+			return parser.newError("key \"" + key + "\" is already defined")
+		}
+		target = child
+	}
+
+	key := keys[len(keys)-1]
+	if _, exist := target.values[key]; exist {
+		return parser.newError("key \"" + strings.Join(keys, ".") + "\" is already defined")
+	}
+	target.set(key, value)
+	target.locate(key, strings.Count(parser.content[:start], "\n")+1, start-strings.LastIndex(parser.content[:start], "\n"))
+	return nil
+}
+
+/*
+parseKey read a bare, quoted or dotted key
+*/
+func (parser *tomlParser) parseKey() ([]string, error) {
+
+	var keys []string
+	for {
+		parser.skipSpaces()
+		var key string
+		switch parser.peek() {
+		case '"':
+			value, err := parser.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = value
+		case '\'':
+			value, err := parser.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = value
+		default:
+			start := parser.position
+			for parser.position < len(parser.content) && tomlBareKeyPattern.MatchString(parser.content[parser.position:parser.position+1]) {
+				parser.position++
+			}
+			if start == parser.position {
+				return nil, parser.newError("a key was expected")
+			}
+			key = parser.content[start:parser.position]
+		}
+		keys = append(keys, key)
+
+		parser.skipSpaces()
+		if parser.peek() != '.' {
+			return keys, nil
+		}
+		parser.position++
+	}
+}
+
+/*
+parseValue read a string, number, boolean, date-time, array or inline table
+*/
+func (parser *tomlParser) parseValue() (interface{}, error) {
+
+	switch {
+
+	case parser.hasPrefix(`"""`):
+		return parser.parseMultilineString(`"""`)
+
+	case parser.hasPrefix(`'''`):
+		return parser.parseMultilineString(`'''`)
+
+	case parser.peek() == '"':
+		return parser.parseBasicString()
+
+	case parser.peek() == '\'':
+		return parser.parseLiteralString()
+
+	case parser.peek() == '[':
+		return parser.parseArray()
+
+	case parser.peek() == '{':
+		return parser.parseInlineTable()
+	}
+
+	start := parser.position
+	for parser.position < len(parser.content) && !strings.ContainsRune(" \t\r\n,]}#", rune(parser.peek())) {
+		parser.position++
+	}
+
+	// Date-times may have a space between the date and the time
+	if tomlDateTimePattern.MatchString(parser.content[start:parser.position]) && parser.peek() == ' ' {
+		end := parser.position + 1
+		for end < len(parser.content) && !strings.ContainsRune(" \t\r\n,]}#", rune(parser.content[end])) {
+			end++
+		}
+		if tomlDateTimePattern.MatchString(parser.content[start:end]) {
+			parser.position = end
+		}
+	}
+
+	token := parser.content[start:parser.position]
+	switch {
+	case token == "true":
+		return true, nil
+	case token == "false":
+		return false, nil
+	case token == "inf" || token == "+inf":
+		return math.Inf(1), nil
+	case token == "-inf":
+		return math.Inf(-1), nil
+	case token == "nan" || token == "+nan" || token == "-nan":
+		return math.NaN(), nil
+	case tomlDateTimePattern.MatchString(token):
+		return token, nil
+	case tomlIntegerPattern.MatchString(token):
+		if value, err := strconv.ParseInt(strings.Replace(token, "_", "", -1), 10, 0); err == nil {
+			return int(value), nil
+		}
+	case len(token) > 2 && tomlPrefixedPattern[token[:2]] != nil && tomlPrefixedPattern[token[:2]].MatchString(token):
+		if value, err := strconv.ParseInt(strings.Replace(token, "_", "", -1), 0, 0); err == nil {
+			return int(value), nil
+		}
+	case tomlFloatPattern.MatchString(token):
+		if value, err := strconv.ParseFloat(strings.Replace(token, "_", "", -1), 64); err == nil {
+			return value, nil
+		}
+	}
+
+	parser.position = start
+	if token == "" {
+		return nil, parser.newError("a value was expected")
+	}
+	return nil, parser.newError("invalid value \"" + token + "\"")
+}
+
+/*
+parseArray read an array, which can span several lines with comments and a trailing comma
+*/
+func (parser *tomlParser) parseArray() (interface{}, error) {
+
+	items := []interface{}{}
+	parser.position++
+	for {
+		parser.skipBlank()
+		if parser.peek() == ']' {
+			parser.position++
+			return items, nil
+		}
+
+		item, err := parser.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		parser.skipBlank()
+		switch parser.peek() {
+		case ',':
+			parser.position++
+		case ']':
+		default:
+			return nil, parser.newError("',' or ']' was expected in array")
+		}
+	}
+}
+
+/*
+parseInlineTable read an inline table, which must be on one line
+*/
+func (parser *tomlParser) parseInlineTable() (interface{}, error) {
+
+	table := newOrderedObject()
+	parser.inlines[table] = true
+	parser.position++
+	parser.skipSpaces()
+	if parser.peek() == '}' {
+		parser.position++
+		return table, nil
+	}
+
+	for {
+		parser.skipSpaces()
+		if err := parser.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		parser.skipSpaces()
+		switch parser.peek() {
+		case ',':
+			parser.position++
+		case '}':
+			parser.position++
+			return table, nil
+		default:
+			return nil, parser.newError("',' or '}' was expected in inline table")
+		}
+	}
+}
+
+/*
+parseBasicString read a double quoted string with its escape sequences
+*/
+func (parser *tomlParser) parseBasicString() (string, error) {
+
+	var buffer bytes.Buffer
+	parser.position++
+	for {
+		switch character := parser.peek(); {
+		case character == '"':
+			parser.position++
+			return buffer.String(), nil
+		case character == 0 && parser.position == len(parser.content), character == '\n':
+			return "", parser.newError("unterminated string")
+		case character == '\\':
+			if err := parser.parseEscape(&buffer); err != nil {
+				return "", err
+			}
+		default:
+			buffer.WriteByte(character)
+			parser.position++
+		}
+	}
+}
+
+/*
+parseLiteralString read a single quoted string, without escape sequences
+*/
+func (parser *tomlParser) parseLiteralString() (string, error) {
+
+	parser.position++
+	end := strings.IndexAny(parser.content[parser.position:], "'\n")
+	if end < 0 || parser.content[parser.position+end] != '\'' {
+		return "", parser.newError("unterminated string")
+	}
+	value := parser.content[parser.position : parser.position+end]
+	parser.position += end + 1
+	return value, nil
+}
+
+/*
+parseMultilineString read a multi-line basic (""") or literal (”') string
+*/
+func (parser *tomlParser) parseMultilineString(delimiter string) (string, error) {
+
+	parser.position += len(delimiter)
+
+	// A new line right after the opening delimiter is trimmed
+	if parser.hasPrefix("\r\n") {
+		parser.position += 2
+	} else if parser.peek() == '\n' {
+		parser.position++
+	}
+
+	var buffer bytes.Buffer
+	for {
+		switch character := parser.peek(); {
+
+		case parser.position == len(parser.content):
+			return "", parser.newError("unterminated multi-line string")
+
+		case parser.hasPrefix(delimiter):
+			// Up to two quotes can be written just before the closing delimiter
+			quotes := len(delimiter)
+			for parser.position+quotes < len(parser.content) && parser.content[parser.position+quotes] == delimiter[0] && quotes < len(delimiter)+2 {
+				quotes++
+			}
+			buffer.WriteString(strings.Repeat(delimiter[:1], quotes-len(delimiter)))
+			parser.position += quotes
+			return buffer.String(), nil
+
+		case character == '\\' && delimiter == `"""`:
+			// A line ending backslash trims all the following white spaces and new lines
+			rest := strings.TrimLeft(parser.content[parser.position+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				parser.position = len(parser.content) - len(strings.TrimLeft(rest, " \t\r\n"))
+				continue
+			}
+			if err := parser.parseEscape(&buffer); err != nil {
+				return "", err
+			}
+
+		default:
+			buffer.WriteByte(character)
+			parser.position++
+		}
+	}
+}
+
+/*
+parseEscape read an escape sequence of a basic string into buffer
+*/
+func (parser *tomlParser) parseEscape(buffer *bytes.Buffer) error {
+
+	parser.position++
+	character := parser.peek()
+	if escaped, known := map[byte]string{'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", 'e': "\x1b", '"': "\"", '\\': "\\"}[character]; known {
+		buffer.WriteString(escaped)
+		parser.position++
+		return nil
+	}
+
+	length := map[byte]int{'u': 4, 'U': 8}[character]
+	if length == 0 || parser.position+1+length > len(parser.content) {
+		return parser.newError(fmt.Sprintf("invalid escape sequence \"\\%c\"", character))
+	}
+	code, err := strconv.ParseUint(parser.content[parser.position+1:parser.position+1+length], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return parser.newError("invalid unicode escape sequence")
+	}
+	buffer.WriteRune(rune(code))
+	parser.position += 1 + length
+	return nil
+}
+
+/*
+Encode the configuration in TOML, child Configurations become tables. TOML has no null, so a nil Value is an error
+naming its key
+*/
+func (codec TOMLCodec) Encode(configuration Configuration) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	if err := writeTOMLTable(&buffer, configuration, nil); err != nil {
+		return nil, newError("eliteConfiguration.TOMLCodec.Encode()", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+writeTOMLTable write the key/value pairs of the configuration then its child Configurations as sub tables
+*/
+func writeTOMLTable(buffer *bytes.Buffer, configuration Configuration, path []string) error {
+
+	var children []string
+	for _, key := range configuration.Keys() {
+		value := configuration.Property(key).Value()
+		if _, isConfiguration := value.(Configuration); isConfiguration {
+			children = append(children, key)
+			continue
+		}
+		text, err := tomlValue(value)
+		if err != nil {
+			return fmt.Errorf("%v: %v", strings.Join(append(path, key), "."), err)
+		}
+		buffer.WriteString(tomlKey(key) + " = " + text + "\n")
+	}
+
+	for _, key := range children {
+		childPath := append(append([]string{}, path...), key)
+		quotedPath := make([]string, len(childPath))
+		for index, name := range childPath {
+			quotedPath[index] = tomlKey(name)
+		}
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString("[" + strings.Join(quotedPath, ".") + "]\n")
+		if err := writeTOMLTable(buffer, configuration.Property(key).Value().(Configuration), childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+tomlKey return the key, quoted if it's not a bare key
+*/
+func tomlKey(key string) string {
+
+	if tomlBareKeyPattern.MatchString(key) {
+		return key
+	}
+	return tomlQuote(key)
+}
+
+/*
+tomlValue return the inline TOML representation of a value
+*/
+func tomlValue(value interface{}) (string, error) {
+
+	switch typedValue := value.(type) {
+
+	case nil:
+		return "", errors.New("null values can't be written in TOML")
+
+	case string:
+		if tomlDateTimePattern.MatchString(typedValue) {
+			return typedValue, nil
+		}
+		return tomlQuote(typedValue), nil
+
+	case time.Time:
+		return typedValue.Format(time.RFC3339Nano), nil
+
+	case bool:
+		return strconv.FormatBool(typedValue), nil
+
+	case float32, float64:
+		floatValue, _ := toFloat(typedValue)
+		switch {
+		case math.IsInf(floatValue, 1):
+			return "inf", nil
+		case math.IsInf(floatValue, -1):
+			return "-inf", nil
+		case math.IsNaN(floatValue):
+			return "nan", nil
+		}
+		text := strconv.FormatFloat(floatValue, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".e") {
+			text += ".0"
+		}
+		return text, nil
+
+	case Configuration:
+		items := make([]string, 0, typedValue.Size())
+		for _, key := range typedValue.Keys() {
+			if item := typedValue.Property(key).Value(); item != nil {
+				text, err := tomlValue(item)
+				if err != nil {
+					return "", err
+				}
+				items = append(items, tomlKey(key)+" = "+text)
+			}
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+
+	case map[string]interface{}:
+		items := make([]string, 0, len(typedValue))
+		for _, key := range sortedKeys(typedValue) {
+			if typedValue[key] != nil {
+				text, err := tomlValue(typedValue[key])
+				if err != nil {
+					return "", err
+				}
+				items = append(items, tomlKey(key)+" = "+text)
+			}
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+
+	case fmt.Stringer:
+		return tomlQuote(typedValue.String()), nil
+	}
+
+	if items, isSequence := toYAMLSequence(value); isSequence {
+		texts := make([]string, len(items))
+		for index, item := range items {
+			text, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			texts[index] = text
+		}
+		return "[" + strings.Join(texts, ", ") + "]", nil
+	}
+	if intValue, isInt := toInt(value); isInt {
+		return strconv.Itoa(intValue), nil
+	}
+	return "", fmt.Errorf("value %v (%T) can't be encoded in TOML", value, value)
+}
+
+/*
+tomlQuote return the basic string representation of the text
+*/
+func tomlQuote(text string) string {
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('"')
+	for _, character := range text {
+		switch {
+		case character == '"' || character == '\\':
+			buffer.WriteByte('\\')
+			buffer.WriteRune(character)
+		case character == '\n':
+			buffer.WriteString(`\n`)
+		case character == '\t':
+			buffer.WriteString(`\t`)
+		case character == '\r':
+			buffer.WriteString(`\r`)
+		case character < 0x20 || character == 0x7f:
+			buffer.WriteString(fmt.Sprintf(`\u%04X`, character))
+		default:
+			buffer.WriteRune(character)
+		}
+	}
+	buffer.WriteByte('"')
+	return buffer.String()
+}
@@ -0,0 +1,278 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+stringValue return the Value of the named Property converted to string
+*/
+func stringValue(configuration Configuration, requiredName string) (string, error) {
+
+	value, err := configuration.Value(requiredName)
+	if err != nil {
+		return "", err
+	}
+	if typedValue, ok := toString(value); ok {
+		return typedValue, nil
+	}
+	return "", newTypeError("StringValue", requiredName, value, "string")
+}
+
+/*
+intValue return the Value of the named Property converted to int
+*/
+func intValue(configuration Configuration, requiredName string) (int, error) {
+
+	value, err := configuration.Value(requiredName)
+	if err != nil {
+		return 0, err
+	}
+	if typedValue, ok := toInt(value); ok {
+		return typedValue, nil
+	}
+	return 0, newTypeError("IntValue", requiredName, value, "int")
+}
+
+/*
+boolValue return the Value of the named Property converted to bool
+*/
+func boolValue(configuration Configuration, requiredName string) (bool, error) {
+
+	value, err := configuration.Value(requiredName)
+	if err != nil {
+		return false, err
+	}
+	if typedValue, ok := toBool(value); ok {
+		return typedValue, nil
+	}
+	return false, newTypeError("BoolValue", requiredName, value, "bool")
+}
+
+/*
+floatValue return the Value of the named Property converted to float64
+*/
+func floatValue(configuration Configuration, requiredName string) (float64, error) {
+
+	value, err := configuration.Value(requiredName)
+	if err != nil {
+		return 0, err
+	}
+	if typedValue, ok := toFloat(value); ok {
+		return typedValue, nil
+	}
+	return 0, newTypeError("FloatValue", requiredName, value, "float64")
+}
+
+/*
+durationValue return the Value of the named Property converted to time.Duration
+*/
+func durationValue(configuration Configuration, requiredName string) (time.Duration, error) {
+
+	value, err := configuration.Value(requiredName)
+	if err != nil {
+		return 0, err
+	}
+	if typedValue, ok := toDuration(value); ok {
+		return typedValue, nil
+	}
+	return 0, newTypeError("DurationValue", requiredName, value, "time.Duration")
+}
+
+/*
+stringSliceValue return the Value of the named Property converted to []string
+*/
+func stringSliceValue(configuration Configuration, requiredName string) ([]string, error) {
+
+	value, err := configuration.Value(requiredName)
+	if err != nil {
+		return nil, err
+	}
+	if typedValue, ok := toStringSlice(value); ok {
+		return typedValue, nil
+	}
+	return nil, newTypeError("StringSliceValue", requiredName, value, "[]string")
+}
+
+/*
+newTypeError return a new configurationError describing a Value which can't be converted to the expected type
+*/
+func newTypeError(methodName string, requiredName string, value interface{}, expectedType string) error {
+
+	return newError("Configuration."+methodName+"(\""+requiredName+"\")", fmt.Errorf("Value %v (%T) can't be converted to %v", value, value, expectedType))
+}
+
+/*
+toString convert a raw Value to string. Strings, fmt.Stringer, booleans and numbers (5432 and not 5432.0 for an
+integral float) are accepted
+*/
+func toString(value interface{}) (string, bool) {
+
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case fmt.Stringer:
+		return typedValue.String(), true
+	case bool:
+		return strconv.FormatBool(typedValue), true
+	case float32:
+		return strconv.FormatFloat(float64(typedValue), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(typedValue), true
+	}
+	return "", false
+}
+
+/*
+toInt convert a raw Value to int. Floats are accepted only when integral, strings when they are valid integers
+*/
+func toInt(value interface{}) (int, bool) {
+
+	switch typedValue := value.(type) {
+	case int:
+		return typedValue, true
+	case int8:
+		return int(typedValue), true
+	case int16:
+		return int(typedValue), true
+	case int32:
+		return int(typedValue), true
+	case int64:
+		return int(typedValue), int64(int(typedValue)) == typedValue
+	case uint:
+		return int(typedValue), typedValue <= math.MaxInt
+	case uint8:
+		return int(typedValue), true
+	case uint16:
+		return int(typedValue), true
+	case uint32:
+		return int(typedValue), uint64(typedValue) <= math.MaxInt
+	case uint64:
+		return int(typedValue), typedValue <= math.MaxInt
+	case float32:
+		return toInt(float64(typedValue))
+	case float64:
+		if typedValue != math.Trunc(typedValue) || typedValue < math.MinInt || typedValue >= math.MaxInt {
+			return 0, false
+		}
+		return int(typedValue), true
+	case json.Number:
+		if intValue, err := typedValue.Int64(); err == nil {
+			return toInt(intValue)
+		}
+		if floatValue, err := typedValue.Float64(); err == nil {
+			return toInt(floatValue)
+		}
+	case string:
+		if intValue, err := strconv.ParseInt(strings.TrimSpace(typedValue), 10, 0); err == nil {
+			return int(intValue), true
+		}
+	}
+	return 0, false
+}
+
+/*
+toBool convert a raw Value to bool. Strings are accepted when strconv.ParseBool accepts them
+*/
+func toBool(value interface{}) (bool, bool) {
+
+	switch typedValue := value.(type) {
+	case bool:
+		return typedValue, true
+	case string:
+		if boolValue, err := strconv.ParseBool(strings.TrimSpace(typedValue)); err == nil {
+			return boolValue, true
+		}
+	}
+	return false, false
+}
+
+/*
+toFloat convert a raw Value to float64. All numbers are accepted, strings when they are valid floats
+*/
+func toFloat(value interface{}) (float64, bool) {
+
+	switch typedValue := value.(type) {
+	case float64:
+		return typedValue, true
+	case float32:
+		return float64(typedValue), true
+	case json.Number:
+		if floatValue, err := typedValue.Float64(); err == nil {
+			return floatValue, true
+		}
+	case string:
+		if floatValue, err := strconv.ParseFloat(strings.TrimSpace(typedValue), 64); err == nil {
+			return floatValue, true
+		}
+	default:
+		if intValue, ok := toInt(value); ok {
+			return float64(intValue), true
+		}
+	}
+	return 0, false
+}
+
+/*
+toDuration convert a raw Value to time.Duration. Strings are parsed with time.ParseDuration ("5s"),
+integral numbers are read as nanoseconds
+*/
+func toDuration(value interface{}) (time.Duration, bool) {
+
+	switch typedValue := value.(type) {
+	case time.Duration:
+		return typedValue, true
+	case string:
+		if durationValue, err := time.ParseDuration(strings.TrimSpace(typedValue)); err == nil {
+			return durationValue, true
+		}
+	default:
+		if intValue, ok := toInt(value); ok {
+			return time.Duration(intValue), true
+		}
+	}
+	return 0, false
+}
+
+/*
+toStringSlice convert a raw Value to []string. Arrays must only contain strings, a single string is split on commas
+*/
+func toStringSlice(value interface{}) ([]string, bool) {
+
+	switch typedValue := value.(type) {
+	case []string:
+		return append([]string{}, typedValue...), true
+	case []interface{}:
+		returnValue := make([]string, 0, len(typedValue))
+		for _, item := range typedValue {
+			stringItem, ok := item.(string)
+			if stringer, isStringer := item.(fmt.Stringer); isStringer {
+				stringItem, ok = stringer.String(), true
+			}
+			if !ok {
+				return nil, false
+			}
+			returnValue = append(returnValue, stringItem)
+		}
+		return returnValue, true
+	case string:
+		returnValue := []string{}
+		if strings.TrimSpace(typedValue) != "" {
+			for _, item := range strings.Split(typedValue, ",") {
+				returnValue = append(returnValue, strings.TrimSpace(item))
+			}
+		}
+		return returnValue, true
+	}
+	return nil, false
+}
@@ -0,0 +1,914 @@
+/*
+Copyright (c) 2016 EliteSystems. All rights reserved.
+*/
+package eliteConfiguration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+YAMLCodec reads and writes YAML files: the top level mapping holds the properties and nested mappings become
+child Configurations. Only the common subset of YAML is supported (no anchors, aliases, tags or multiple documents)
+and the Configuration's Name is not stored
+*/
+type YAMLCodec struct {
+}
+
+var (
+	yamlIntPattern   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloatPattern = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	yamlPlainPattern = regexp.MustCompile(`^[^-?:,\[\]{}#&*!|>'"%@` + "`" + `\s]([^:#]|:[^\s]|[^\s]#)*$`)
+)
+
+/*
+yamlLine is a line of a YAML document with its indentation
+*/
+type yamlLine struct {
+	number int
+	indent int
+	raw    string
+	text   string
+}
+
+/*
+yamlDecoder is the internal state used to decode a YAML document
+*/
+type yamlDecoder struct {
+	lines []*yamlLine
+	index int
+}
+
+/*
+Decode the YAML content into the configuration
+*/
+func (codec YAMLCodec) Decode(content []byte, configuration Configuration) (Configuration, error) {
+
+	decoder, err := newYAMLDecoder(content)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decoder.parseNode(-1)
+	if err != nil {
+		return nil, err
+	}
+	if line, remaining := decoder.peek(); remaining {
+		return nil, decoder.newError(line, "unexpected content")
+	}
+
+	switch object := value.(type) {
+	case nil:
+		return configuration, nil
+	case *orderedObject:
+		return fromObject(object, configuration), nil
+	}
+	return nil, newError("eliteConfiguration.YAMLCodec.Decode()", errors.New("The YAML document must be a mapping"))
+}
+
+/*
+newYAMLDecoder split the content in lines and remove the document markers
+*/
+func newYAMLDecoder(content []byte) (*yamlDecoder, error) {
+
+	decoder := &yamlDecoder{}
+	started := false
+	for index, raw := range strings.Split(string(content), "\n") {
+
+		raw = strings.TrimSuffix(raw, "\r")
+		text := strings.TrimLeft(raw, " ")
+		line := &yamlLine{number: index + 1, indent: len(raw) - len(text), raw: raw, text: text}
+
+		switch {
+		case line.indent == 0 && strings.HasPrefix(text, "%") && !started:
+			continue
+		case line.indent == 0 && (text == "---" || strings.HasPrefix(text, "--- ")):
+			if started || strings.TrimSpace(stripYAMLComment(text[3:])) != "" {
+				return nil, decoder.newError(line, "multiple documents and document's content on the \"---\" line are not supported")
+			}
+			started = true
+			continue
+		case line.indent == 0 && (text == "..." || strings.HasPrefix(text, "... ")):
+			return decoder, nil
+		case !isBlankYAML(text):
+			started = true
+		}
+		decoder.lines = append(decoder.lines, line)
+	}
+	return decoder, nil
+}
+
+/*
+newError return a new configurationError located at the line
+*/
+func (decoder *yamlDecoder) newError(line *yamlLine, message string) error {
+
+	return newError("eliteConfiguration.YAMLCodec.Decode()", fmt.Errorf("line %v: %v", line.number, message))
+}
+
+/*
+isBlankYAML check if the text (without indentation) is empty or a comment
+*/
+func isBlankYAML(text string) bool {
+
+	return strings.TrimSpace(text) == "" || strings.HasPrefix(strings.TrimLeft(text, " \t"), "#")
+}
+
+/*
+peek return the next significant line, skipping blank lines and comments
+*/
+func (decoder *yamlDecoder) peek() (*yamlLine, bool) {
+
+	for decoder.index < len(decoder.lines) && isBlankYAML(decoder.lines[decoder.index].text) {
+		decoder.index++
+	}
+	if decoder.index < len(decoder.lines) {
+		return decoder.lines[decoder.index], true
+	}
+	return nil, false
+}
+
+/*
+parseNode parse the node indented more than parentIndent, or return nil if there's none
+*/
+func (decoder *yamlDecoder) parseNode(parentIndent int) (interface{}, error) {
+
+	line, exist := decoder.peek()
+	if !exist || line.indent <= parentIndent {
+		return nil, nil
+	}
+	return decoder.parseBlock(line)
+}
+
+/*
+parseBlock parse the node starting at line: a sequence, a mapping or an inline value
+*/
+func (decoder *yamlDecoder) parseBlock(line *yamlLine) (interface{}, error) {
+
+	if strings.HasPrefix(line.text, "\t") {
+		return nil, decoder.newError(line, "tabs are not allowed for indentation")
+	}
+	if isYAMLSequenceEntry(line.text) {
+		return decoder.parseSequence(line.indent)
+	}
+	if _, _, isEntry, err := splitYAMLEntry(line.text); err != nil {
+		return nil, decoder.newError(line, err.Error())
+	} else if isEntry {
+		return decoder.parseMapping(line.indent)
+	}
+
+	decoder.index++
+	return decoder.parseInline(line, line.text)
+}
+
+/*
+isYAMLSequenceEntry check if the text (without indentation) is a block sequence's entry
+*/
+func isYAMLSequenceEntry(text string) bool {
+
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+/*
+parseSequence parse all the block sequence's entries with the indent
+*/
+func (decoder *yamlDecoder) parseSequence(indent int) (interface{}, error) {
+
+	items := []interface{}{}
+	for {
+
+		line, exist := decoder.peek()
+		if exist && strings.HasPrefix(line.text, "\t") {
+			return nil, decoder.newError(line, "tabs are not allowed for indentation")
+		}
+		if !exist || line.indent < indent || (line.indent == indent && !isYAMLSequenceEntry(line.text)) {
+			return items, nil
+		}
+		if line.indent > indent || !isYAMLSequenceEntry(line.text) {
+			return nil, decoder.newError(line, "bad indentation of a sequence entry")
+		}
+
+		var item interface{}
+		var err error
+		rest := strings.TrimLeft(line.text[1:], " ")
+		switch {
+
+		case isBlankYAML(rest):
+			decoder.index++
+			item, err = decoder.parseNode(indent)
+
+		case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+			decoder.index++
+			item, err = decoder.parseBlockScalar(line, rest, indent)
+
+		default:
+			// The entry's content is parsed in place, as a node indented after the "- "
+			line.indent += len(line.text) - len(rest)
+			line.text = rest
+			item, err = decoder.parseBlock(line)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
+/*
+parseMapping parse all the block mapping's entries with the indent
+*/
+func (decoder *yamlDecoder) parseMapping(indent int) (interface{}, error) {
+
+	object := newOrderedObject()
+	for {
+
+		line, exist := decoder.peek()
+		if exist && strings.HasPrefix(line.text, "\t") {
+			return nil, decoder.newError(line, "tabs are not allowed for indentation")
+		}
+		if !exist || line.indent < indent {
+			return object, nil
+		}
+
+		key, rest, isEntry, err := splitYAMLEntry(line.text)
+		switch {
+		case err != nil:
+			return nil, decoder.newError(line, err.Error())
+		case line.indent > indent || !isEntry:
+			return nil, decoder.newError(line, "bad indentation of a mapping entry")
+		}
+		if _, exist := object.values[key]; exist {
+			return nil, decoder.newError(line, "duplicated key \""+key+"\"")
+		}
+
+		decoder.index++
+		value, err := decoder.parseEntryValue(line, rest, indent)
+		if err != nil {
+			return nil, err
+		}
+		object.set(key, value)
+		object.locate(key, line.number, line.indent+1)
+	}
+}
+
+/*
+parseEntryValue parse the value of a mapping's entry: inline, block scalar or nested node
+*/
+func (decoder *yamlDecoder) parseEntryValue(line *yamlLine, rest string, indent int) (interface{}, error) {
+
+	switch {
+
+	case isBlankYAML(rest):
+		// A sequence can have the same indent than its mapping's key
+		if next, exist := decoder.peek(); exist && next.indent == indent && isYAMLSequenceEntry(next.text) {
+			return decoder.parseSequence(indent)
+		}
+		return decoder.parseNode(indent)
+
+	case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+		return decoder.parseBlockScalar(line, rest, indent)
+	}
+
+	return decoder.parseInline(line, rest)
+}
+
+/*
+splitYAMLEntry split a block mapping's entry into its key and the rest of the line
+*/
+func splitYAMLEntry(text string) (key string, rest string, isEntry bool, err error) {
+
+	switch {
+
+	case text == "" || strings.ContainsAny(text[:1], "[{#|>"):
+		return "", "", false, nil
+
+	case strings.HasPrefix(text, "? "):
+		return "", "", false, errors.New("complex mapping keys are not supported")
+
+	case text[0] == '"' || text[0] == '\'':
+		key, length, err := parseYAMLQuoted(text)
+		if err != nil {
+			return "", "", false, err
+		}
+		rest = strings.TrimLeft(text[length:], " ")
+		if rest == ":" || strings.HasPrefix(rest, ": ") {
+			return key, strings.TrimLeft(rest[1:], " "), true, nil
+		}
+		return "", "", false, nil
+	}
+
+	for index := 0; index < len(text); index++ {
+		switch {
+		case text[index] == '#' && index > 0 && text[index-1] == ' ':
+			return "", "", false, nil
+		case text[index] == ':' && (index+1 == len(text) || text[index+1] == ' '):
+			return strings.TrimRight(text[:index], " "), strings.TrimLeft(text[index+1:], " "), true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+/*
+stripYAMLComment remove the comment at the end of a plain text
+*/
+func stripYAMLComment(text string) string {
+
+	if strings.HasPrefix(text, "#") {
+		return ""
+	}
+	if index := strings.Index(text, " #"); index >= 0 {
+		return text[:index]
+	}
+	return text
+}
+
+/*
+parseInline parse an inline value: flow collection, quoted or plain scalar
+*/
+func (decoder *yamlDecoder) parseInline(line *yamlLine, text string) (interface{}, error) {
+
+	switch {
+
+	case text == "":
+		return nil, nil
+
+	case strings.ContainsAny(text[:1], "&*!"):
+		return nil, decoder.newError(line, "anchors, aliases and tags are not supported")
+
+	case text[0] == '[' || text[0] == '{':
+		// Flow collections may continue on the next lines
+		for !isBalancedYAMLFlow(text) && decoder.index < len(decoder.lines) {
+			text += " " + strings.TrimSpace(decoder.lines[decoder.index].text)
+			decoder.index++
+		}
+		parser := yamlFlowParser{text: text}
+		value, err := parser.parseValue()
+		if err == nil && !isBlankYAML(strings.TrimSpace(parser.text[parser.position:])) {
+			err = errors.New("unexpected content after a flow collection")
+		}
+		if err != nil {
+			return nil, decoder.newError(line, err.Error())
+		}
+		return value, nil
+
+	case text[0] == '"' || text[0] == '\'':
+		value, length, err := parseYAMLQuoted(text)
+		if err == nil && !isBlankYAML(strings.TrimSpace(text[length:])) {
+			err = errors.New("unexpected content after a quoted scalar")
+		}
+		if err != nil {
+			return nil, decoder.newError(line, err.Error())
+		}
+		return value, nil
+	}
+
+	return resolveYAMLScalar(strings.TrimSpace(stripYAMLComment(text))), nil
+}
+
+/*
+parseBlockScalar parse a literal (|) or folded (>) block scalar whose content is indented more than parentIndent
+*/
+func (decoder *yamlDecoder) parseBlockScalar(line *yamlLine, header string, parentIndent int) (interface{}, error) {
+
+	literal := header[0] == '|'
+	chomping := byte(0)
+	contentIndent := 0
+	for _, indicator := range []byte(strings.TrimSpace(stripYAMLComment(header[1:]))) {
+		switch {
+		case (indicator == '-' || indicator == '+') && chomping == 0:
+			chomping = indicator
+		case indicator >= '1' && indicator <= '9' && contentIndent == 0:
+			contentIndent = int(indicator - '0')
+			if parentIndent > 0 {
+				contentIndent += parentIndent
+			}
+		default:
+			return nil, decoder.newError(line, "invalid block scalar header \""+header+"\"")
+		}
+	}
+
+	// Read all the lines of the block, blank ones included
+	var lines []string
+	for ; decoder.index < len(decoder.lines); decoder.index++ {
+		current := decoder.lines[decoder.index]
+		blank := strings.TrimSpace(current.raw) == ""
+		if !blank && contentIndent == 0 {
+			if current.indent <= parentIndent {
+				break
+			}
+			contentIndent = current.indent
+		}
+		if !blank && current.indent < contentIndent {
+			break
+		}
+		if len(current.raw) > contentIndent {
+			lines = append(lines, current.raw[contentIndent:])
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	// Trailing blank lines are only kept with the "+" chomping
+	trailing := 0
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	decoder.index -= trailing
+	if decoder.index < 0 {
+		decoder.index = 0
+	}
+
+	var content string
+	if literal {
+		content = strings.Join(lines, "\n")
+	} else {
+		content = foldYAMLLines(lines)
+	}
+
+	switch {
+	case len(lines) == 0 || chomping == '-':
+	case chomping == '+':
+		content += strings.Repeat("\n", trailing+1)
+	default:
+		content += "\n"
+	}
+	return content, nil
+}
+
+/*
+foldYAMLLines join the lines of a folded block scalar: line breaks between normal lines become spaces
+*/
+func foldYAMLLines(lines []string) string {
+
+	moreIndented := func(line string) bool {
+		return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+	}
+
+	var buffer bytes.Buffer
+	for index, line := range lines {
+		if index > 0 {
+			previous := lines[index-1]
+			switch {
+			case line == "":
+				buffer.WriteString("\n")
+			case previous == "" && !moreIndented(line):
+			case previous == "" || moreIndented(line) || moreIndented(previous):
+				buffer.WriteString("\n")
+			default:
+				buffer.WriteString(" ")
+			}
+		}
+		buffer.WriteString(line)
+	}
+	return buffer.String()
+}
+
+/*
+isBalancedYAMLFlow check if all the brackets of a flow collection are closed
+*/
+func isBalancedYAMLFlow(text string) bool {
+
+	depth := 0
+	var quote byte
+	for index := 0; index < len(text); index++ {
+		switch character := text[index]; {
+		case quote == '"' && character == '\\':
+			index++
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+		case character == '"' || character == '\'':
+			quote = character
+		case character == '[' || character == '{':
+			depth++
+		case character == ']' || character == '}':
+			depth--
+		case character == '#' && index > 0 && text[index-1] == ' ':
+			return depth <= 0
+		}
+	}
+	return depth <= 0 && quote == 0
+}
+
+/*
+parseYAMLQuoted parse the single or double quoted scalar at the beginning of text and return its length
+*/
+func parseYAMLQuoted(text string) (string, int, error) {
+
+	var buffer bytes.Buffer
+	quote := text[0]
+	for index := 1; index < len(text); index++ {
+
+		character := text[index]
+		switch {
+
+		case character == quote && quote == '\'' && index+1 < len(text) && text[index+1] == '\'':
+			buffer.WriteByte('\'')
+			index++
+
+		case character == quote:
+			return buffer.String(), index + 1, nil
+
+		case character == '\\' && quote == '"':
+			if index+1 == len(text) {
+				return "", 0, errors.New("unterminated escape sequence")
+			}
+			index++
+			length := map[byte]int{'x': 2, 'u': 4, 'U': 8}[text[index]]
+			if length > 0 {
+				if index+length >= len(text) {
+					return "", 0, errors.New("invalid escape sequence")
+				}
+				code, err := strconv.ParseUint(text[index+1:index+1+length], 16, 32)
+				if err != nil {
+					return "", 0, errors.New("invalid escape sequence")
+				}
+				buffer.WriteRune(rune(code))
+				index += length
+				continue
+			}
+			escaped, known := map[byte]string{'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v",
+				'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\", 'N': "\u0085", '_': " ",
+				'L': " ", 'P': " "}[text[index]]
+			if !known {
+				return "", 0, errors.New("invalid escape sequence \"\\" + string(text[index]) + "\"")
+			}
+			buffer.WriteString(escaped)
+
+		default:
+			buffer.WriteByte(character)
+		}
+	}
+	return "", 0, errors.New("unterminated quoted scalar")
+}
+
+/*
+resolveYAMLScalar return the typed value of a plain scalar (null, bool, int, float or string)
+*/
+func resolveYAMLScalar(text string) interface{} {
+
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+
+	switch {
+	case yamlIntPattern.MatchString(text):
+		if value, err := strconv.ParseInt(text, 10, 0); err == nil {
+			return int(value)
+		}
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0o"):
+		base := map[string]int{"0x": 16, "0o": 8}[text[:2]]
+		if value, err := strconv.ParseInt(text[2:], base, 0); err == nil {
+			return int(value)
+		}
+		return text
+	}
+	if yamlFloatPattern.MatchString(text) {
+		if value, err := strconv.ParseFloat(text, 64); err == nil {
+			return value
+		}
+	}
+	return text
+}
+
+/*
+yamlFlowParser is the internal state used to parse a flow collection ([...] or {...})
+*/
+type yamlFlowParser struct {
+	text     string
+	position int
+}
+
+/*
+skipSpaces move the position after the spaces
+*/
+func (parser *yamlFlowParser) skipSpaces() {
+
+	for parser.position < len(parser.text) && parser.text[parser.position] == ' ' {
+		parser.position++
+	}
+}
+
+/*
+parseValue parse the flow value at the position
+*/
+func (parser *yamlFlowParser) parseValue() (interface{}, error) {
+
+	parser.skipSpaces()
+	if parser.position == len(parser.text) {
+		return nil, errors.New("unterminated flow collection")
+	}
+
+	switch parser.text[parser.position] {
+
+	case '[':
+		items := []interface{}{}
+		parser.position++
+		for {
+			parser.skipSpaces()
+			if parser.position < len(parser.text) && parser.text[parser.position] == ']' {
+				parser.position++
+				return items, nil
+			}
+			item, err := parser.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if err := parser.parseSeparator(']'); err != nil {
+				return nil, err
+			}
+		}
+
+	case '{':
+		object := newOrderedObject()
+		parser.position++
+		for {
+			parser.skipSpaces()
+			if parser.position < len(parser.text) && parser.text[parser.position] == '}' {
+				parser.position++
+				return object, nil
+			}
+			key, err := parser.parseScalar(true)
+			if err != nil {
+				return nil, err
+			}
+			var value interface{}
+			parser.skipSpaces()
+			if parser.position < len(parser.text) && parser.text[parser.position] == ':' {
+				parser.position++
+				if value, err = parser.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+			object.set(fmt.Sprint(key), value)
+			if err := parser.parseSeparator('}'); err != nil {
+				return nil, err
+			}
+		}
+
+	case '&', '*', '!':
+		return nil, errors.New("anchors, aliases and tags are not supported")
+	}
+
+	return parser.parseScalar(false)
+}
+
+/*
+parseSeparator read the "," between two items or leave the closing character to be read
+*/
+func (parser *yamlFlowParser) parseSeparator(closing byte) error {
+
+	parser.skipSpaces()
+	switch {
+	case parser.position == len(parser.text):
+		return errors.New("unterminated flow collection")
+	case parser.text[parser.position] == ',':
+		parser.position++
+		return nil
+	case parser.text[parser.position] == closing:
+		return nil
+	}
+	return fmt.Errorf("unexpected character '%c' in flow collection", parser.text[parser.position])
+}
+
+/*
+parseScalar parse a quoted or plain scalar in a flow collection. Keys are never resolved to typed values
+*/
+func (parser *yamlFlowParser) parseScalar(isKey bool) (interface{}, error) {
+
+	if character := parser.text[parser.position]; character == '"' || character == '\'' {
+		value, length, err := parseYAMLQuoted(parser.text[parser.position:])
+		parser.position += length
+		return value, err
+	}
+
+	start := parser.position
+	for ; parser.position < len(parser.text); parser.position++ {
+		character := parser.text[parser.position]
+		if strings.IndexByte(",[]{}", character) >= 0 {
+			break
+		}
+		if character == ':' && (parser.position+1 == len(parser.text) || strings.IndexByte(" ,[]{}", parser.text[parser.position+1]) >= 0) {
+			break
+		}
+		if character == '#' && parser.position > start && parser.text[parser.position-1] == ' ' {
+			return nil, errors.New("comments are not allowed inside flow collections")
+		}
+	}
+
+	text := strings.TrimSpace(parser.text[start:parser.position])
+	if isKey {
+		return text, nil
+	}
+	return resolveYAMLScalar(text), nil
+}
+
+/*
+Encode the configuration in YAML, child Configurations become nested mappings
+*/
+func (codec YAMLCodec) Encode(configuration Configuration) ([]byte, error) {
+
+	lines, err := yamlLines(configuration)
+	if err != nil {
+		return nil, newError("eliteConfiguration.YAMLCodec.Encode()", err)
+	}
+	if len(lines) == 0 {
+		lines = []string{"{}"}
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+/*
+yamlLines return the block lines of a collection, or nil if the value is an empty collection or a scalar
+*/
+func yamlLines(value interface{}) ([]string, error) {
+
+	var lines []string
+	appendEntry := func(prefix string, item interface{}) error {
+		itemLines, err := yamlLines(item)
+		if err != nil {
+			return err
+		}
+		if itemLines == nil {
+			scalar, err := yamlScalar(item)
+			lines = append(lines, prefix+" "+scalar)
+			return err
+		}
+		// Sequence's entries start on the "- " line, mapping's entries on the next lines
+		if prefix == "-" {
+			lines = append(lines, "- "+itemLines[0])
+			itemLines = itemLines[1:]
+		} else {
+			lines = append(lines, prefix)
+		}
+		for _, itemLine := range itemLines {
+			lines = append(lines, "  "+itemLine)
+		}
+		return nil
+	}
+
+	switch typedValue := value.(type) {
+
+	case Configuration:
+		for _, key := range typedValue.Keys() {
+			if err := appendEntry(yamlKey(key)+":", typedValue.Property(key).Value()); err != nil {
+				return nil, err
+			}
+		}
+		return lines, nil
+
+	case map[string]interface{}:
+		for _, key := range sortedKeys(typedValue) {
+			if err := appendEntry(yamlKey(key)+":", typedValue[key]); err != nil {
+				return nil, err
+			}
+		}
+		return lines, nil
+	}
+
+	if items, isSequence := toYAMLSequence(value); isSequence {
+		for _, item := range items {
+			if err := appendEntry("-", item); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return lines, nil
+}
+
+/*
+toYAMLSequence return the items of a slice or an array
+*/
+func toYAMLSequence(value interface{}) ([]interface{}, bool) {
+
+	if items, isSlice := value.([]interface{}); isSlice {
+		return items, true
+	}
+	if value == nil {
+		return nil, false
+	}
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, reflectValue.Len())
+	for index := range items {
+		items[index] = reflectValue.Index(index).Interface()
+	}
+	return items, true
+}
+
+/*
+yamlKey return the key, quoted if needed
+*/
+func yamlKey(key string) string {
+
+	if isPlainYAML(key) {
+		return key
+	}
+	return yamlQuote(key)
+}
+
+/*
+isPlainYAML check if the text can be written without quotes
+*/
+func isPlainYAML(text string) bool {
+
+	return yamlPlainPattern.MatchString(text) && !strings.HasSuffix(text, " ") && strings.IndexFunc(text, unicode.IsControl) < 0
+}
+
+/*
+yamlScalar return the YAML representation of a scalar or an empty collection
+*/
+func yamlScalar(value interface{}) (string, error) {
+
+	switch typedValue := value.(type) {
+
+	case nil:
+		return "null", nil
+
+	case bool:
+		return strconv.FormatBool(typedValue), nil
+
+	case string:
+		if _, isString := resolveYAMLScalar(typedValue).(string); isString && isPlainYAML(typedValue) {
+			return typedValue, nil
+		}
+		return yamlQuote(typedValue), nil
+
+	case float32, float64:
+		floatValue, _ := toFloat(typedValue)
+		switch {
+		case math.IsInf(floatValue, 1):
+			return ".inf", nil
+		case math.IsInf(floatValue, -1):
+			return "-.inf", nil
+		case math.IsNaN(floatValue):
+			return ".nan", nil
+		}
+
+		// An integral float keeps its decimal point, else it would be loaded back as an int
+		text := strconv.FormatFloat(floatValue, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".e") {
+			text += ".0"
+		}
+		return text, nil
+
+	case Configuration, map[string]interface{}:
+		return "{}", nil
+
+	case fmt.Stringer:
+		return yamlScalar(typedValue.String())
+	}
+
+	if _, isSequence := toYAMLSequence(value); isSequence {
+		return "[]", nil
+	}
+	if intValue, isInt := toInt(value); isInt {
+		return strconv.Itoa(intValue), nil
+	}
+	if uintValue, isUint := value.(uint64); isUint {
+		return strconv.FormatUint(uintValue, 10), nil
+	}
+	return "", fmt.Errorf("value %v (%T) can't be encoded in YAML", value, value)
+}
+
+/*
+yamlQuote return the double quoted representation of the text
+*/
+func yamlQuote(text string) string {
+
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "�")
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(text)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}